@@ -0,0 +1,249 @@
+package assertion
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// gzipMagic starts every gzip stream, AssertTar uses it to decompress .tar.gz archives
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ArchiveOptions configures how AssertZip and AssertTar compare the entries of the archives
+type ArchiveOptions struct {
+	// IgnoreTimestamps doesn't compare the modification times of the entries
+	IgnoreTimestamps bool
+	// IgnoreOrder doesn't compare the order of the entries in the archives
+	IgnoreOrder bool
+	// Rules are the custom assertions of the contents keyed by glob on the entry paths, the same way as AssertFS
+	Rules map[string]AssertionFunc
+}
+
+// archiveEntry is an entry of an archive, directories are named without their trailing /
+type archiveEntry struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	link    string
+	data    []byte
+}
+
+// AssertZip compares the entries of the actual and expected zip archives without extracting them
+// and returns the result and message listing the differences per entry path:
+// missing and extra entries, modes, modification times, order and contents, see ArchiveOptions.
+// Example usage:
+//
+//	match, message := AssertZip(bytes.NewReader(bundle), expected, ArchiveOptions{
+//		IgnoreTimestamps: true,
+//		Rules: map[string]AssertionFunc{
+//			"*.json":         EmbeddedJSON,
+//			"bin/*":          AssertSHA256,
+//			"META-INF/BUILD": SkipAssertion,
+//		},
+//	})
+func AssertZip(actual io.Reader, expected io.Reader, opts ArchiveOptions) (bool, string) {
+	actualEntries, err := readZip(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual is not a valid zip archive: %v", err)
+	}
+	expectedEntries, err := readZip(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected is not a valid zip archive: %v", err)
+	}
+	return compareArchives(actualEntries, expectedEntries, opts)
+}
+
+// AssertTar compares the entries of the actual and expected tar archives without extracting them,
+// gzip compressed archives are decompressed, e.g. .tar.gz bundles, see AssertZip
+func AssertTar(actual io.Reader, expected io.Reader, opts ArchiveOptions) (bool, string) {
+	actualEntries, err := readTar(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual is not a valid tar archive: %v", err)
+	}
+	expectedEntries, err := readTar(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected is not a valid tar archive: %v", err)
+	}
+	return compareArchives(actualEntries, expectedEntries, opts)
+}
+
+// readZip reads the entries of the zip archive in their order
+func readZip(r io.Reader) ([]archiveEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]archiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		entry := archiveEntry{
+			name:    strings.TrimSuffix(file.Name, "/"),
+			mode:    file.Mode(),
+			modTime: file.Modified,
+		}
+		if !entry.mode.IsDir() {
+			if entry.data, err = readZipFile(file); err != nil {
+				return nil, fmt.Errorf("%s: %w", entry.name, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readZipFile reads the decompressed contents of the zip entry
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// readTar reads the entries of the tar archive in their order, decompressing it first if it is gzip compressed
+func readTar(r io.Reader) ([]archiveEntry, error) {
+	buffered := bufio.NewReader(r)
+	if magic, _ := buffered.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		decompressed, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer decompressed.Close()
+		r = decompressed
+	} else {
+		r = buffered
+	}
+
+	reader := tar.NewReader(r)
+	entries := []archiveEntry{}
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := archiveEntry{
+			name:    strings.TrimSuffix(header.Name, "/"),
+			mode:    header.FileInfo().Mode(),
+			modTime: header.ModTime,
+			link:    header.Linkname,
+		}
+		if entry.data, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.name, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// compareArchives compares the entries of both archives matched by path and returns the result and message
+func compareArchives(actual []archiveEntry, expected []archiveEntry, opts ArchiveOptions) (bool, string) {
+	messages := []string{}
+	actualEntries, duplicates := indexEntries(actual, "actual")
+	messages = append(messages, duplicates...)
+	expectedEntries, duplicates := indexEntries(expected, "expected")
+	messages = append(messages, duplicates...)
+
+	for _, entry := range actual {
+		if _, ok := expectedEntries[entry.name]; !ok {
+			messages = append(messages, fmt.Sprintf("entry %s not found in expected", entry.name))
+		}
+	}
+	for _, entry := range expected {
+		if _, ok := actualEntries[entry.name]; !ok {
+			messages = append(messages, fmt.Sprintf("entry %s not found in actual", entry.name))
+		}
+	}
+	if !opts.IgnoreOrder {
+		messages = append(messages, compareEntryOrder(actual, expected, actualEntries, expectedEntries)...)
+	}
+	for i := range expected {
+		entry := &expected[i]
+		actualEntry, ok := actualEntries[entry.name]
+		if !ok || expectedEntries[entry.name] != entry {
+			continue
+		}
+		messages = append(messages, compareEntry(*actualEntry, *entry, opts)...)
+	}
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "\n")
+	}
+	return true, ""
+}
+
+// indexEntries returns the entries by path, keeping the first of duplicated paths which are reported
+func indexEntries(entries []archiveEntry, side string) (map[string]*archiveEntry, []string) {
+	index := map[string]*archiveEntry{}
+	messages := []string{}
+	for i := range entries {
+		if _, ok := index[entries[i].name]; ok {
+			messages = append(messages, fmt.Sprintf("entry %s is duplicated in %s", entries[i].name, side))
+			continue
+		}
+		index[entries[i].name] = &entries[i]
+	}
+	return index, messages
+}
+
+// compareEntryOrder reports the entries found on both sides which moved, the fewest entries putting the others out of order,
+// positions count every entry of the archive from 1
+func compareEntryOrder(actual []archiveEntry, expected []archiveEntry, actualEntries map[string]*archiveEntry, expectedEntries map[string]*archiveEntry) []string {
+	positions := func(entries []archiveEntry, others map[string]*archiveEntry) ([]string, map[string]int) {
+		names, position := []string{}, map[string]int{}
+		for i, entry := range entries {
+			if _, ok := position[entry.name]; ok {
+				continue
+			}
+			position[entry.name] = i + 1
+			if _, ok := others[entry.name]; ok {
+				names = append(names, entry.name)
+			}
+		}
+		return names, position
+	}
+	actualNames, actualPositions := positions(actual, expectedEntries)
+	expectedNames, expectedPositions := positions(expected, actualEntries)
+
+	// the entries kept in the longest common subsequence are in order, the others moved
+	messages := []string{}
+	for _, op := range diffLines(actualNames, expectedNames) {
+		if op.kind == '-' {
+			messages = append(messages, fmt.Sprintf("entry %s is entry %d in actual and %d in expected", op.line, actualPositions[op.line], expectedPositions[op.line]))
+		}
+	}
+	return messages
+}
+
+// compareEntry compares the mode, modification time, link and contents of the entry
+func compareEntry(actual archiveEntry, expected archiveEntry, opts ArchiveOptions) []string {
+	messages := []string{}
+	if actual.mode != expected.mode {
+		messages = append(messages, fmt.Sprintf("entry %s has mode %v in actual and %v in expected", expected.name, actual.mode, expected.mode))
+	}
+	if !opts.IgnoreTimestamps && !actual.modTime.Equal(expected.modTime) {
+		messages = append(messages, fmt.Sprintf("entry %s was modified at %s in actual and %s in expected",
+			expected.name, actual.modTime.Format(time.RFC3339), expected.modTime.Format(time.RFC3339)))
+	}
+	if actual.link != expected.link {
+		messages = append(messages, fmt.Sprintf("entry %s links to %q in actual and %q in expected", expected.name, actual.link, expected.link))
+	}
+	if !actual.mode.IsDir() && !expected.mode.IsDir() {
+		if message := compareContents("entry "+expected.name, expected.name, actual.data, expected.data, opts.Rules); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,171 @@
+package assertion
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// testEntry describes an entry of the archives built by the tests, directories end with /
+type testEntry struct {
+	name    string
+	data    string
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+var archiveTime = time.Date(2021, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+func buildZip(t *testing.T, entries []testEntry) *bytes.Buffer {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: entry.modTime}
+		header.SetMode(entry.mode)
+		file, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(entry.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func buildTarGz(t *testing.T, entries []testEntry) *bytes.Buffer {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	compressed := gzip.NewWriter(buffer)
+	writer := tar.NewWriter(compressed)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: int64(entry.mode.Perm()), ModTime: entry.modTime, Size: int64(len(entry.data)), Typeflag: tar.TypeReg}
+		if entry.mode.IsDir() {
+			header.Typeflag = tar.TypeDir
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(entry.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func TestAssertArchives(t *testing.T) {
+	expected := []testEntry{
+		{name: "bin/", mode: fs.ModeDir | 0o755, modTime: archiveTime},
+		{name: "bin/app", data: "\x7fELF\x00", mode: 0o755, modTime: archiveTime},
+		{name: "config.json", data: `{"name": "app", "port": 80}`, mode: 0o644, modTime: archiveTime},
+		{name: "README.md", data: "# App\nversion 1\n", mode: 0o644, modTime: archiveTime},
+	}
+
+	testTable := []struct {
+		name            string
+		actual          []testEntry
+		opts            ArchiveOptions
+		expectedOk      bool
+		expectedMessage string
+	}{
+		{
+			name:       "Test same entries",
+			actual:     expected,
+			expectedOk: true,
+		},
+		{
+			name:            "Test moved entry",
+			actual:          []testEntry{expected[3], expected[0], expected[1], expected[2]},
+			expectedOk:      false,
+			expectedMessage: "entry README.md is entry 1 in actual and 4 in expected",
+		},
+		{
+			name: "Test ignoring timestamps and order with rules",
+			actual: []testEntry{
+				{name: "README.md", data: "# App\nversion 2\n", mode: 0o644, modTime: archiveTime.Add(time.Hour)},
+				{name: "config.json", data: `{"port": 80, "name": "app"}`, mode: 0o644, modTime: archiveTime.Add(time.Hour)},
+				{name: "bin/", mode: fs.ModeDir | 0o755, modTime: archiveTime.Add(time.Hour)},
+				{name: "bin/app", data: "\x7fELF\x00", mode: 0o755, modTime: archiveTime.Add(time.Hour)},
+			},
+			opts: ArchiveOptions{
+				IgnoreTimestamps: true,
+				IgnoreOrder:      true,
+				Rules: map[string]AssertionFunc{
+					"*.json":    EmbeddedJSON,
+					"README.md": AssertStringWithDistance(1),
+				},
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test differences per entry",
+			actual: []testEntry{
+				{name: "bin/", mode: fs.ModeDir | 0o755, modTime: archiveTime},
+				{name: "config.json", data: `{"name": "app", "port": 80}`, mode: 0o600, modTime: archiveTime.Add(time.Hour)},
+				{name: "bin/app", data: "\x7fELF\x01", mode: 0o755, modTime: archiveTime},
+				{name: "CHANGELOG.md", data: "v1", mode: 0o644, modTime: archiveTime},
+			},
+			expectedOk: false,
+			expectedMessage: "entry CHANGELOG.md not found in expected\n" +
+				"entry README.md not found in actual\n" +
+				"entry bin/app is entry 3 in actual and 2 in expected\n" +
+				"entry bin/app\nExpected: \"\\x7fELF\\x00\"\nActual:   \"\\x7fELF\\x01\"\n" +
+				"entry config.json has mode -rw------- in actual and -rw-r--r-- in expected\n" +
+				"entry config.json was modified at 2021-01-01T11:00:00Z in actual and 2021-01-01T10:00:00Z in expected",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name+" in zip", func(t *testing.T) {
+			ok, message := AssertZip(buildZip(t, tt.actual), buildZip(t, expected), tt.opts)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertZip returned %v: %s", ok, message)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("AssertZip returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+		t.Run(tt.name+" in tar.gz", func(t *testing.T) {
+			ok, message := AssertTar(buildTarGz(t, tt.actual), buildTarGz(t, expected), tt.opts)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertTar returned %v: %s", ok, message)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("AssertTar returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestAssertArchives_errors(t *testing.T) {
+	valid := buildZip(t, nil).Bytes()
+	if ok, message := AssertZip(bytes.NewReader([]byte("not a zip")), bytes.NewReader(valid), ArchiveOptions{}); ok || message != "Actual is not a valid zip archive: zip: not a valid zip file" {
+		t.Errorf("AssertZip returned %v: %s", ok, message)
+	}
+	if ok, message := AssertTar(bytes.NewReader([]byte{0x1f, 0x8b, 0}), buildTarGz(t, nil), ArchiveOptions{}); ok || message != "Actual is not a valid tar archive: unexpected EOF" {
+		t.Errorf("AssertTar returned %v: %s", ok, message)
+	}
+
+	uncompressed := &bytes.Buffer{}
+	writer := tar.NewWriter(uncompressed)
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, message := AssertTar(uncompressed, buildTarGz(t, nil), ArchiveOptions{}); !ok {
+		t.Errorf("AssertTar failed on an uncompressed archive: %s", message)
+	}
+}
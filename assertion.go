@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/smarty/assertions"
@@ -17,6 +18,7 @@ var removeIndexRegex = regexp.MustCompile(`\[\d+\]`)
 // actual is the actual value to be compared
 // expected is the expected value to be compared
 // customAssertions is the map of custom assertions defined for the path or type
+// opts are the options changing how the values are walked and reported, e.g. WithStrictRules()
 // Example usage:
 //
 //	customAssertions := map[string]AssertionFunc{
@@ -27,9 +29,54 @@ var removeIndexRegex = regexp.MustCompile(`\[\d+\]`)
 //
 // match, message := Assert(actual, expected, customAssertions)
 // returns the result and message
-func Assert(actual any, expected any, customAssertions map[string]AssertionFunc) (bool, string) {
+func Assert(actual any, expected any, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	w := newWalker(customAssertions, opts...)
 	// If not custom assertion defined, use default assertion for the whole object
-	return assertWithPaths(reflect.ValueOf(actual), reflect.ValueOf(expected), customAssertions, "$")
+	match, message := w.walk(reflect.ValueOf(actual), reflect.ValueOf(expected), "$")
+	if w.unifiedDiff && len(w.failedPaths) > 0 {
+		message = formatMessage(message, "%s", renderDiff(w.config, w.failedPaths, reflect.ValueOf(actual), reflect.ValueOf(expected)))
+	}
+	if w.goLiteral && !match {
+		message = formatMessage(message, "Actual value as Go literal:\n%s", GoLiteral(actual))
+	}
+	if w.truncated {
+		message = formatMessage(message, "Stopped at the maximum number of failures (%d), the remaining values were not compared", w.failures)
+	}
+	return w.checkUnusedRules(match, message)
+}
+
+// walker holds the configuration and the state of a single comparison
+// usedRules, visitedPaths and visitedTypes are recorded while walking to report unused custom assertions
+type walker struct {
+	config
+	customAssertions map[string]AssertionFunc
+	usedRules        map[string]bool
+	visitedPaths     map[string]bool
+	visitedTypes     map[string]bool
+	// aliases maps promoted paths to the embedded paths they are also addressed by, see WithPromotedFields
+	aliases map[string]string
+	// failures counts the failures found, the walk is truncated once it reaches maxFailures
+	failures  int
+	truncated bool
+	// failedPaths holds the paths of the failures, used to render the unified diff
+	failedPaths map[string]bool
+	// emptyPaths maps the paths of nil values and empty slices and maps on both sides to their static types,
+	// the rules below them are checked on the types as they are never visited
+	emptyPaths map[string]reflect.Type
+}
+
+// newWalker returns a walker using the custom assertions and the options given
+func newWalker(customAssertions map[string]AssertionFunc, opts ...Option) *walker {
+	return &walker{
+		config:           newConfig(opts...),
+		customAssertions: customAssertions,
+		usedRules:        map[string]bool{},
+		visitedPaths:     map[string]bool{},
+		visitedTypes:     map[string]bool{},
+		aliases:          map[string]string{},
+		failedPaths:      map[string]bool{},
+		emptyPaths:       map[string]reflect.Type{},
+	}
 }
 
 // assertWithPaths recursively compares the actual and expected values
@@ -46,96 +93,259 @@ func assertWithPaths(
 	customAssertions map[string]AssertionFunc,
 	path string,
 ) (bool, string) {
+	return newWalker(customAssertions).walk(actual, expected, path)
+}
+
+// walk recursively compares the actual and expected values at the given path
+// and returns the result and message, see assertWithPaths
+func (w *walker) walk(actual reflect.Value, expected reflect.Value, path string) (bool, string) {
 	match, message := true, ""
-	// Dereference pointers
-	for actual.Kind() == reflect.Ptr && expected.Kind() == reflect.Ptr {
-		actual = actual.Elem()
-		expected = expected.Elem()
-	}
+	// keep the values before dereferencing to report nil pointers with their types
+	originalActual, originalExpected := actual, expected
+	actual, expected = w.dereference(actual, expected)
 
-	// handle nil pointers
+	// handle nil pointers, the rules of the path are used and the paths below it are resolved on its static type
 	if !actual.IsValid() && !expected.IsValid() {
+		if typ := getType(originalActual, originalExpected); typ != nil {
+			typ = derefType(typ)
+			w.visit(path, typ)
+			w.customAssertion(path, typ)
+			w.emptyPaths[normalizePath(path)] = typ
+		}
 		return true, ""
 	}
 	typ := getType(actual, expected)
+	w.visit(path, typ)
+
+	// replace the values by the normalizer defined for the path or type before comparing them
+	if normalizer, ok := w.normalizer(path, typ); ok {
+		actual, expected = w.dereference(normalizeValue(normalizer, actual), normalizeValue(normalizer, expected))
+		typ = getType(actual, expected)
+	}
 
 	// check if custom assertion is defined for the path
-	if customAssertionFunc, ok := hasCustomAssertion(path, typ, customAssertions); ok {
-		return assertValue(path, customAssertionFunc, actual, expected)
+	if customAssertionFunc, ok := w.customAssertion(path, typ); ok {
+		if isEmbeddedJSON(customAssertionFunc) {
+			return w.walkEmbeddedJSON(actual, expected, path)
+		}
+		return w.assertValue(path, customAssertionFunc, actual, expected)
 	}
 
 	if !actual.IsValid() || !expected.IsValid() {
-		return false, formatMessage(message, "Path: %s\nExpected: %v\nActual: %v", path, expected, actual)
+		return false, w.fail(path, "Path: %s\nExpected: %s\nActual: %s", path, formatValue(originalExpected), formatValue(originalActual))
+	}
+
+	// handle values of different kinds, e.g. the values of map[string]any
+	if actual.Kind() != expected.Kind() {
+		return w.assertValue(path, defaultAssertionFunc, actual, expected)
 	}
 
 	switch actual.Kind() {
 	case reflect.Struct:
 		// handle time.Time
 		if actual.Type() == reflect.TypeOf(time.Time{}) {
-			return assertValue(path, nil, actual, expected)
+			return w.assertValue(path, nil, actual, expected)
 		}
-		// handle structs not matching in fields
-		if actual.NumField() != expected.NumField() {
-			return assertValue(path, defaultAssertionFunc, actual, expected)
+		if w.promote {
+			return w.walkPromotedFields(actual, expected, path)
 		}
 
-		for i := 0; i < actual.NumField(); i++ {
+		for i := 0; i < actual.NumField() && !w.stop(); i++ {
 			field := actual.Type().Field(i)
-			fieldPath := path + "." + field.Name
-			// check if expected has the same field
+			fieldPath := path + "." + w.fieldName(field)
+			// check if expected has the same field, and keep comparing the other fields
 			if !expected.FieldByName(field.Name).IsValid() {
-				return false, formatMessage(message, "Path: %s\nField %s not found in expected", fieldPath, field.Name)
+				match = false
+				message = formatMessage(message, "%s", w.fail(fieldPath, "Path: %s\nField %s not found in expected", fieldPath, field.Name))
+				continue
 			}
-			if listMatch, listMessage := assertWithPaths(actual.Field(i), expected.FieldByName(field.Name), customAssertions, fieldPath); !listMatch {
+			if listMatch, listMessage := w.walk(actual.Field(i), expected.FieldByName(field.Name), fieldPath); !listMatch {
 				match = false
 				message = formatMessage(message, "%s", listMessage)
 			}
 		}
+		// report the fields of expected missing in actual
+		for i := 0; i < expected.NumField(); i++ {
+			field := expected.Type().Field(i)
+			if _, ok := actual.Type().FieldByName(field.Name); !ok {
+				if w.stop() {
+					break
+				}
+				fieldPath := path + "." + w.fieldName(field)
+				match = false
+				message = formatMessage(message, "%s", w.fail(fieldPath, "Path: %s\nField %s not found in actual", fieldPath, field.Name))
+			}
+		}
 	case reflect.Slice, reflect.Array:
 		if actual.Len() != expected.Len() {
-			return assertValue(path, defaultAssertionFunc, actual, expected)
+			return w.assertValue(path, defaultAssertionFunc, actual, expected)
 		}
-		for i := 0; i < actual.Len(); i++ {
-			if listMatch, listMessage := assertWithPaths(actual.Index(i), expected.Index(i), customAssertions, fmt.Sprintf("%s[%d]", path, i)); !listMatch {
+		if actual.Len() == 0 {
+			w.emptyPaths[normalizePath(path)] = actual.Type()
+		}
+		for i := 0; i < actual.Len() && !w.stop(); i++ {
+			if listMatch, listMessage := w.walk(actual.Index(i), expected.Index(i), fmt.Sprintf("%s[%d]", path, i)); !listMatch {
 				match = false
 				message = formatMessage(message, "%s", listMessage)
 			}
 		}
 	case reflect.Map:
-		if actual.Len() != expected.Len() {
-			return assertValue(path, defaultAssertionFunc, actual, expected)
+		// handle maps not matching in key types, their keys can't be looked up in each other
+		if actual.Type().Key() != expected.Type().Key() {
+			return w.assertValue(path, defaultAssertionFunc, actual, expected)
 		}
-		for _, key := range actual.MapKeys() {
-			// check if expected has the same key
+		if actual.Len() == 0 {
+			w.emptyPaths[normalizePath(path)] = actual.Type()
+		}
+		for _, key := range sortedMapKeys(actual) {
+			if w.stop() {
+				break
+			}
+			keyPath := fmt.Sprintf("%s.%v", path, key.Interface())
+			// check if expected has the same key, and keep comparing the other keys
 			if !expected.MapIndex(key).IsValid() {
-				return false, formatMessage(message, "Path: %s\nKey %s not found in expected", path+"."+key.String(), key.String())
+				match = false
+				message = formatMessage(message, "%s", w.fail(keyPath, "Path: %s\nKey %v not found in expected", keyPath, key.Interface()))
+				continue
 			}
-			if listMatch, listMessage := assertWithPaths(actual.MapIndex(key), expected.MapIndex(key), customAssertions, fmt.Sprintf("%s.%v", path, key.Interface())); !listMatch {
+			if listMatch, listMessage := w.walk(actual.MapIndex(key), expected.MapIndex(key), keyPath); !listMatch {
 				match = false
 				message = formatMessage(message, "%s", listMessage)
 			}
 		}
+		// report the keys of expected missing in actual
+		for _, key := range sortedMapKeys(expected) {
+			if !actual.MapIndex(key).IsValid() {
+				if w.stop() {
+					break
+				}
+				keyPath := fmt.Sprintf("%s.%v", path, key.Interface())
+				match = false
+				message = formatMessage(message, "%s", w.fail(keyPath, "Path: %s\nKey %v not found in actual", keyPath, key.Interface()))
+			}
+		}
 	default:
+		// compare JSON numbers by value, e.g. 1.0 and 1
+		if equalJSONNumbers(actual, expected) {
+			return true, ""
+		}
 		// check for custom assertions with path
-		return assertValue(path, defaultAssertionFunc, actual, expected)
+		return w.assertValue(path, defaultAssertionFunc, actual, expected)
 	}
 	return match, message
 }
 
+// dereference dereferences the pointers on both sides and unwraps interfaces, e.g. the values of map[string]any,
+// an interface is kept if a custom assertion or normalizer is defined for its type
+func (w *walker) dereference(actual reflect.Value, expected reflect.Value) (reflect.Value, reflect.Value) {
+	for {
+		switch {
+		case (actual.Kind() == reflect.Interface || expected.Kind() == reflect.Interface) && !w.hasTypeRule(getType(actual, expected)):
+			actual, expected = unwrapInterface(actual), unwrapInterface(expected)
+		case actual.Kind() == reflect.Ptr && expected.Kind() == reflect.Ptr:
+			actual, expected = actual.Elem(), expected.Elem()
+		default:
+			return actual, expected
+		}
+	}
+}
+
+// hasTypeRule checks if a custom assertion or normalizer is defined for the type
+func (w *walker) hasTypeRule(typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	_, assertion := w.customAssertions[typ.String()]
+	_, normalizer := w.normalizers[typ.String()]
+	return assertion || normalizer
+}
+
+// assertValue compares the values at the path, see assertValue, counting and recording the failure
+// the values of documents are converted to the types expected by the helper, see documentValue
+func (w *walker) assertValue(path string, customAssertion AssertionFunc, actual reflect.Value, expected reflect.Value) (bool, string) {
+	if w.documents {
+		actual, expected = documentValue(customAssertion, actual), documentValue(customAssertion, expected)
+	}
+	match, message := assertValue(path, customAssertion, actual, expected)
+	if !match {
+		w.failures++
+		w.failedPaths[path] = true
+	}
+	return match, message
+}
+
+// fail counts and records a failure found while walking at the path and returns its message formatted with fmt.Sprintf
+func (w *walker) fail(path string, format string, a ...any) string {
+	w.failures++
+	w.failedPaths[path] = true
+	return fmt.Sprintf(format, a...)
+}
+
+// stop checks if the maximum number of failures is reached before walking the next value, truncating the walk
+func (w *walker) stop() bool {
+	if w.maxFailures > 0 && w.failures >= w.maxFailures {
+		w.truncated = true
+		return true
+	}
+	return false
+}
+
+// visit records the path and the type reached while walking, used to suggest replacements for unused rules
+func (w *walker) visit(path string, typ reflect.Type) {
+	w.visitedPaths[normalizePath(path)] = true
+	if typ != nil {
+		w.visitedTypes[typ.String()] = true
+	}
+}
+
+// customAssertion returns the custom assertion defined for the path, one of its aliases, or type and marks its rule as used
+// the path takes precedence over its aliases, and both over the type
+func (w *walker) customAssertion(path string, typ reflect.Type) (AssertionFunc, bool) {
+	key, ok := "", false
+	for _, candidate := range append([]string{path}, w.aliasesOf(path)...) {
+		if key, ok = customAssertionKey(candidate, nil, w.customAssertions); ok {
+			break
+		}
+	}
+	if !ok {
+		key, ok = customAssertionKey(path, typ, w.customAssertions)
+	}
+	if !ok {
+		return nil, false
+	}
+	w.usedRules[key] = true
+	return w.customAssertions[key], true
+}
+
 // hasCustomAssertion checks if custom assertion is defined for the path or type of the field
 func hasCustomAssertion(path string, fieldType reflect.Type, customAssertions map[string]AssertionFunc) (AssertionFunc, bool) {
+	key, ok := customAssertionKey(path, fieldType, customAssertions)
+	if !ok {
+		return nil, false
+	}
+	return customAssertions[key], true
+}
+
+// customAssertionKey returns the key of the custom assertion, or normalizer, defined for the path or type of the field
+// the path takes precedence over the type
+func customAssertionKey[F any](path string, fieldType reflect.Type, customAssertions map[string]F) (string, bool) {
 	// check if custom assertion is defined for the path
 	// replace index with [] to match the path
-	if customAssertionByPath, ok := customAssertions[removeIndexRegex.ReplaceAllString(path, "[]")]; ok {
-		return customAssertionByPath, true
+	if _, ok := customAssertions[normalizePath(path)]; ok {
+		return normalizePath(path), true
 	}
 	// check if custom assertion is defined for the type
 	if fieldType != nil {
-		if customAssertionByType, ok := customAssertions[fieldType.String()]; ok {
-			return customAssertionByType, true
+		if _, ok := customAssertions[fieldType.String()]; ok {
+			return fieldType.String(), true
 		}
 	}
-	return nil, false
+	return "", false
+}
+
+// normalizePath replaces the indexes in the path with [] to match the paths used as custom assertion keys
+func normalizePath(path string) string {
+	return removeIndexRegex.ReplaceAllString(path, "[]")
 }
 
 // assertValue checks if the actual and expected values are matching
@@ -150,11 +360,36 @@ func assertValue(path string, customAssertion AssertionFunc, actual reflect.Valu
 	isMatching, newMessage := assertions.So(getValue(actual), assertions.SoFunc(customAssertion), getValue(expected))
 
 	if !isMatching {
+		// render the values of the default assertion the same way as the other messages, see formatValue
+		if isDefaultAssertion(customAssertion) {
+			newMessage = formatExpectedActual(newMessage, actual, expected)
+		}
+		// add a readable diff of long or multi-line strings
+		if actual.Kind() == reflect.String && expected.Kind() == reflect.String {
+			if diff := stringDiff(actual.String(), expected.String()); diff != "" {
+				newMessage = formatMessage(newMessage, "%s", diff)
+			}
+		}
 		return false, fmt.Sprintf("Path: %s\n%s", path, newMessage)
 	}
 	return true, ""
 }
 
+// isDefaultAssertion checks if the assertion function is the default assertion function shouldEqual
+func isDefaultAssertion(customAssertion AssertionFunc) bool {
+	return reflect.ValueOf(customAssertion).Pointer() == reflect.ValueOf(defaultAssertionFunc).Pointer()
+}
+
+// formatExpectedActual replaces the expected and actual values rendered by shouldEqual in the message with formatValue,
+// keeping the verdict and diff following them
+func formatExpectedActual(message string, actual reflect.Value, expected reflect.Value) string {
+	verdict := strings.Index(message, "\n(Should equal")
+	if !strings.HasPrefix(message, "Expected: ") || verdict == -1 {
+		return message
+	}
+	return fmt.Sprintf("Expected: %s\nActual:   %s%s", formatValue(expected), formatValue(actual), message[verdict:])
+}
+
 // getValue returns the interface value of the reflect value or nil if not valid
 func getValue(value reflect.Value) any {
 	if !value.IsValid() {
@@ -232,14 +232,14 @@ func TestAssertWithPaths_genericChecks(t *testing.T) {
 			actual:          nil,
 			expected:        1,
 			expectedMatch:   false,
-			expectedMessage: "Path: $\nExpected: 1\nActual: <invalid reflect.Value>",
+			expectedMessage: "Path: $\nExpected: 1\nActual: nil",
 		},
 		{
 			name:            "Test with nil expected",
 			actual:          1,
 			expected:        nil,
 			expectedMatch:   false,
-			expectedMessage: "Path: $\nExpected: <invalid reflect.Value>\nActual: 1",
+			expectedMessage: "Path: $\nExpected: nil\nActual: 1",
 		},
 		{
 			name:          "Test with nil pointers",
@@ -252,14 +252,14 @@ func TestAssertWithPaths_genericChecks(t *testing.T) {
 			actual:          &struct{ Name string }{},
 			expected:        nil,
 			expectedMatch:   false,
-			expectedMessage: "Path: $\nExpected: <invalid reflect.Value>\nActual: &{}",
+			expectedMessage: "Path: $\nExpected: nil\nActual: &struct { Name string }{Name: \"\"}",
 		},
 		{
 			name:            "Test with nil expected pointer",
 			actual:          nil,
 			expected:        &struct{ Name string }{},
 			expectedMatch:   false,
-			expectedMessage: "Path: $\nExpected: &{}\nActual: <invalid reflect.Value>",
+			expectedMessage: "Path: $\nExpected: &struct { Name string }{Name: \"\"}\nActual: nil",
 		},
 		{
 			name:     "test with custom assertion on type",
@@ -300,7 +300,7 @@ func TestAssertWithPaths_genericChecks(t *testing.T) {
 				Time time.Time
 			}{Time: testTime.Add(time.Second)},
 			expectedMatch:   false,
-			expectedMessage: fmt.Sprintf("Path: $.Time\nExpected: time.Time{%v}\nActual:   time.Time{%v}\n(Should equal)!\n", testTime.Add(time.Second), testTime),
+			expectedMessage: fmt.Sprintf("Path: $.Time\nExpected: time.Time(%s)\nActual:   time.Time(%s)\n(Should equal)!\n", testTime.Add(time.Second).Format(time.RFC3339Nano), testTime.Format(time.RFC3339Nano)),
 		},
 		{
 			name: "Test with invalid values and custom assertion",
@@ -359,7 +359,7 @@ func TestAssertWithPaths_structs(t *testing.T) {
 			actual:          testTime,
 			expected:        testTime.Add(time.Second),
 			expectedMatch:   false,
-			expectedMessage: fmt.Sprintf("Path: $\nExpected: time.Time{%v}\nActual:   time.Time{%v}\n(Should equal)!\n", testTime.Add(time.Second), testTime),
+			expectedMessage: fmt.Sprintf("Path: $\nExpected: time.Time(%s)\nActual:   time.Time(%s)\n(Should equal)!\n", testTime.Add(time.Second).Format(time.RFC3339Nano), testTime.Format(time.RFC3339Nano)),
 		},
 		{
 			name:          "Test with nested struct",
@@ -394,7 +394,7 @@ func TestAssertWithPaths_structs(t *testing.T) {
 				Test int
 			}{Name: "test"}},
 			expectedMatch:   false,
-			expectedMessage: "Path: $.Sub.Age\nField Age not found in expected",
+			expectedMessage: "Path: $.Sub.Age\nField Age not found in expected\nPath: $.Sub.Test\nField Test not found in actual",
 		},
 	}
 
@@ -530,7 +530,7 @@ func TestAssertWithPaths_Maps(t *testing.T) {
 			actual:          map[string]int{"a": 1, "b": 2},
 			expected:        map[string]int{"a": 1},
 			expectedMatch:   false,
-			expectedMessage: "Path: $\nExpected: map[string]int{\"a\":1}\nActual:   map[string]int{\"a\":1, \"b\":2}\n(Should equal)!\n",
+			expectedMessage: "Path: $.b\nKey b not found in expected",
 		},
 		{
 			name:            "Test with maps not matching in values",
@@ -566,7 +566,7 @@ func TestAssertWithPaths_Maps(t *testing.T) {
 			actual:          map[string]int{"a": 1, "b": 2},
 			expected:        map[string]int{"a": 1, "c": 2},
 			expectedMatch:   false,
-			expectedMessage: "Path: $.b\nKey b not found in expected",
+			expectedMessage: "Path: $.b\nKey b not found in expected\nPath: $.c\nKey c not found in actual",
 		},
 		{
 			name:            "Test with map of int",
@@ -781,3 +781,129 @@ func TestGetType(t *testing.T) {
 	}
 
 }
+
+func TestAssert_failures(t *testing.T) {
+	type actualStruct struct {
+		Name  string
+		Age   int
+		Email string
+	}
+	type expectedStruct struct {
+		Name    string
+		Age     int
+		Address string
+	}
+
+	testTable := []struct {
+		name            string
+		actual          any
+		expected        any
+		opts            []Option
+		expectedMatch   bool
+		expectedMessage string
+	}{
+		{
+			name:          "Test with missing field and other mismatches",
+			actual:        actualStruct{Name: "a", Age: 1, Email: "a"},
+			expected:      expectedStruct{Name: "b", Age: 2},
+			expectedMatch: false,
+			expectedMessage: "Path: $.Name\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n" +
+				"Path: $.Age\nExpected: 2\nActual:   1\n(Should equal)!\n" +
+				"Path: $.Email\nField Email not found in expected\n" +
+				"Path: $.Address\nField Address not found in actual",
+		},
+		{
+			name:          "Test with structs not matching in fields and other mismatches",
+			actual:        actualStruct{Name: "a", Age: 1, Email: "a"},
+			expected:      struct{ Name, Phone string }{Name: "b"},
+			expectedMatch: false,
+			expectedMessage: "Path: $.Name\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n" +
+				"Path: $.Age\nField Age not found in expected\n" +
+				"Path: $.Email\nField Email not found in expected\n" +
+				"Path: $.Phone\nField Phone not found in actual",
+		},
+		{
+			name:          "Test with missing keys and other mismatches",
+			actual:        map[int]int{1: 1, 2: 2, 3: 3},
+			expected:      map[int]int{1: 2, 4: 2, 5: 3},
+			expectedMatch: false,
+			expectedMessage: "Path: $.1\nExpected: 2\nActual:   1\n(Should equal)!\n" +
+				"Path: $.2\nKey 2 not found in expected\n" +
+				"Path: $.3\nKey 3 not found in expected\n" +
+				"Path: $.4\nKey 4 not found in actual\n" +
+				"Path: $.5\nKey 5 not found in actual",
+		},
+		{
+			name:            "Test with slices not matching in length rendered by formatValue",
+			actual:          []time.Time{time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			expected:        []time.Time{},
+			expectedMatch:   false,
+			expectedMessage: "Path: $\nExpected: []time.Time{}\nActual:   []time.Time{time.Time(2021-01-01T00:00:00Z)}\n(Should equal)!",
+		},
+		{
+			name:            "Test with fail fast",
+			actual:          []int{1, 2, 3},
+			expected:        []int{4, 5, 6},
+			opts:            []Option{WithFailFast()},
+			expectedMatch:   false,
+			expectedMessage: "Path: $[0]\nExpected: 4\nActual:   1\n(Should equal)!\nStopped at the maximum number of failures (1), the remaining values were not compared",
+		},
+		{
+			name:            "Test with fail fast stopping at the last value",
+			actual:          struct{ ID int }{ID: 1},
+			expected:        struct{ ID int }{ID: 2},
+			opts:            []Option{WithFailFast()},
+			expectedMatch:   false,
+			expectedMessage: "Path: $.ID\nExpected: 2\nActual:   1\n(Should equal)!",
+		},
+		{
+			name:            "Test with fail fast stopping at the last key",
+			actual:          map[string]int{"a": 1},
+			expected:        map[string]int{"a": 2},
+			opts:            []Option{WithFailFast()},
+			expectedMatch:   false,
+			expectedMessage: "Path: $.a\nExpected: 2\nActual:   1\n(Should equal)!",
+		},
+		{
+			name: "Test with max failures across nested values",
+			actual: map[string][]int{
+				"a": {1, 2},
+				"b": {3, 4},
+			},
+			expected: map[string][]int{
+				"a": {1, 0},
+				"b": {0, 0},
+			},
+			opts:          []Option{WithMaxFailures(2), WithStrictRules()},
+			expectedMatch: false,
+			expectedMessage: "Path: $.a[1]\nExpected: 0\nActual:   2\n(Should equal)!\n" +
+				"Path: $.b[0]\nExpected: 0\nActual:   3\n(Should equal)!\n" +
+				"Stopped at the maximum number of failures (2), the remaining values were not compared",
+		},
+		{
+			name:            "Test with max failures not reached",
+			actual:          []int{1, 2, 3},
+			expected:        []int{1, 2, 4},
+			opts:            []Option{WithMaxFailures(2)},
+			expectedMatch:   false,
+			expectedMessage: "Path: $[2]\nExpected: 4\nActual:   3\n(Should equal)!",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			match, message := Assert(tt.actual, tt.expected, nil, tt.opts...)
+			if match != tt.expectedMatch {
+				t.Errorf("Expected match: %v, got: %v", tt.expectedMatch, match)
+			}
+			// remove anything after the word "Diff" in the message till end of line
+			re := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+			// Replace matched lines with an empty string.
+			message = re.ReplaceAllString(message, "")
+
+			if message != tt.expectedMessage {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expectedMessage, message)
+			}
+		})
+	}
+}
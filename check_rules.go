@@ -0,0 +1,238 @@
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ruleType is an assertion function built by a helper of this package with the type it compares, used by CheckRules.
+// the type is recorded on every function built rather than by code pointer, as the instantiations of a generic helper,
+// e.g. AssertNumberWithTolerance, may share their code
+type ruleType struct {
+	helper string
+	typ    reflect.Type
+	fn     AssertionFunc
+}
+
+// ruleTypeProbe is passed as the actual value to the assertion functions built by expectsType to read their ruleType
+type ruleTypeProbe struct {
+	rule *ruleType
+}
+
+// ruleTypeCode is the code pointer shared by the assertion functions built by expectsType
+var ruleTypeCode = reflect.ValueOf((&ruleType{}).assert).Pointer()
+
+// assert compares the values with the assertion function, or records the ruleType in a probe
+func (r *ruleType) assert(actual any, expected ...any) string {
+	if probe, ok := actual.(*ruleTypeProbe); ok {
+		probe.rule = r
+		return ""
+	}
+	return r.fn(actual, expected...)
+}
+
+// expectsType returns the assertion function built by helper, recording the type it compares
+func expectsType(helper string, typ reflect.Type, fn AssertionFunc) AssertionFunc {
+	return (&ruleType{helper: helper, typ: typ, fn: fn}).assert
+}
+
+// lookupRuleType returns the helper and the type expected by the assertion function if it was built by a helper of this package
+func lookupRuleType(fn AssertionFunc) (string, reflect.Type, bool) {
+	if fn == nil || reflect.ValueOf(fn).Pointer() != ruleTypeCode {
+		return "", nil, false
+	}
+	probe := &ruleTypeProbe{}
+	fn(probe)
+	return probe.rule.helper, probe.rule.typ, true
+}
+
+// CheckRules validates the custom assertions against the static structure of T
+// and returns an error listing every rule which can never match or compare correctly:
+// paths that can never exist on T, types that never occur in T
+// and assertions of this package expecting a different type than the one found at their path or type.
+// Call it in a unit test or init to catch broken rule maps before they hide real failures.
+// opts are the options used with Assert which change the paths, e.g. WithNaming(JSONTagNames)
+// Example usage:
+//
+//	func TestOrderRules(t *testing.T) {
+//		if err := assertion.CheckRules[Order](orderRules); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+func CheckRules[T any](customAssertions map[string]AssertionFunc, opts ...Option) error {
+	return CheckRulesForType(reflect.TypeOf((*T)(nil)).Elem(), customAssertions, opts...)
+}
+
+// CheckRulesForType is the reflect.Type based equivalent of CheckRules
+func CheckRulesForType(typ reflect.Type, customAssertions map[string]AssertionFunc, opts ...Option) error {
+	cfg := newConfig(opts...)
+	types, dynamic := collectTypes(typ)
+
+	keys := make([]string, 0, len(customAssertions))
+	for key := range customAssertions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		var fieldType reflect.Type
+		if isPathRule(key) {
+			resolved, err := resolveRulePath(cfg, typ, key)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", key, err))
+				continue
+			}
+			fieldType = resolved
+		} else {
+			found, ok := types[key]
+			if !ok && !dynamic {
+				errs = append(errs, fmt.Errorf("rule %q: type never occurs in %s", key, typ))
+				continue
+			}
+			fieldType = found
+		}
+		if helper, expected, ok := lookupRuleType(customAssertions[key]); ok && fieldType != nil && expected != fieldType {
+			errs = append(errs, fmt.Errorf("rule %q: %s expects %s, found %s", key, helper, expected, fieldType))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveRulePath follows the path of the rule on the type the same way assertWithPaths walks values
+// and returns the type found at the end of the path, or nil if it is only known at runtime
+func resolveRulePath(cfg config, typ reflect.Type, key string) (reflect.Type, error) {
+	segments, err := splitRulePath(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		typ = derefType(typ)
+		if typ.Kind() == reflect.Interface {
+			// the dynamic type is unknown, any path below it may exist
+			return nil, nil
+		}
+		if segment == "#" {
+			if typ.Kind() != reflect.String && (typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8) {
+				return nil, fmt.Errorf("%s can't hold an embedded JSON document", typ)
+			}
+			// the embedded document is only known at runtime, any path below it may exist
+			return nil, nil
+		}
+		if segment == "[]" {
+			if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+				return nil, fmt.Errorf("%s is not a slice or array", typ)
+			}
+			typ = typ.Elem()
+			continue
+		}
+		switch {
+		case typ == reflect.TypeOf(time.Time{}):
+			return nil, fmt.Errorf("%s is compared as a whole, it has no field %q", typ, segment)
+		case typ.Kind() == reflect.Map:
+			// any key may exist in a map
+			typ = typ.Elem()
+		case typ.Kind() == reflect.Struct:
+			field, ok := fieldByPathName(cfg, typ, segment)
+			if !ok {
+				return nil, fmt.Errorf("no field %q in %s", segment, typ)
+			}
+			typ = field.Type
+		default:
+			return nil, fmt.Errorf("%s has no field %q", typ, segment)
+		}
+	}
+	return derefType(typ), nil
+}
+
+// fieldByPathName returns the field of the struct type named in paths by name
+// with WithPromotedFields, the fields promoted from embedded structs are also found by their promoted name
+func fieldByPathName(cfg config, typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); cfg.fieldName(field) == name {
+			return field, true
+		}
+	}
+	if cfg.promote {
+		return promotedField(cfg, typ, name)
+	}
+	return reflect.StructField{}, false
+}
+
+// splitRulePath splits the path of a rule into its field names, [] and # segments
+// e.g. "$.Items[].SKU" is split into "Items", "[]" and "SKU", "$.Payload#.id" into "Payload", "#" and "id"
+func splitRulePath(key string) ([]string, error) {
+	if !isPathRule(key) {
+		return nil, fmt.Errorf("path must start with $")
+	}
+	segments := []string{}
+	rest := key[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[]"):
+			segments = append(segments, "[]")
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("indexes never match, use [] instead")
+		case strings.HasPrefix(rest, "#"):
+			segments = append(segments, "#")
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[#")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name")
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("unexpected %q, expected . or []", rest)
+		}
+	}
+	return segments, nil
+}
+
+// collectTypes returns every type reachable from typ by name
+// dynamic is true if an interface is reachable, in which case any other type may occur at runtime
+func collectTypes(typ reflect.Type) (types map[string]reflect.Type, dynamic bool) {
+	types = map[string]reflect.Type{}
+	var collect func(typ reflect.Type)
+	collect = func(typ reflect.Type) {
+		if _, ok := types[typ.String()]; ok {
+			return
+		}
+		types[typ.String()] = typ
+		switch typ.Kind() {
+		case reflect.Interface:
+			dynamic = true
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			collect(typ.Elem())
+		case reflect.Map:
+			collect(typ.Elem())
+		case reflect.Struct:
+			if typ == reflect.TypeOf(time.Time{}) {
+				return
+			}
+			for i := 0; i < typ.NumField(); i++ {
+				collect(typ.Field(i).Type)
+			}
+		}
+	}
+	collect(typ)
+	return types, dynamic
+}
+
+// derefType returns the type pointed to by typ, following all pointers
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
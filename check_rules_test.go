@@ -0,0 +1,209 @@
+package assertion
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type checkRulesAddress struct {
+	Zip    string
+	Street string
+}
+
+type checkRulesCustomer struct {
+	Name    string
+	Address *checkRulesAddress
+}
+
+type checkRulesItem struct {
+	SKU   string
+	Price float64
+}
+
+type checkRulesOrder struct {
+	Customer  checkRulesCustomer
+	Items     []checkRulesItem
+	Totals    map[string]float64
+	CreatedAt time.Time
+}
+
+type checkRulesEvent struct {
+	Name    string
+	Payload any
+}
+
+func TestCheckRules(t *testing.T) {
+	testTable := []struct {
+		name             string
+		customAssertions map[string]AssertionFunc
+		expectedErr      string
+	}{
+		{
+			name: "Test with valid rules",
+			customAssertions: map[string]AssertionFunc{
+				"$":                            SkipAssertion,
+				"$.Customer.Address.Zip":       AssertStringWithDistance(1),
+				"$.Items[].Price":              AssertFloat64WithTolerance(0.01),
+				"$.Totals.net":                 AssertFloat64ToDecimalPlaces(2),
+				"$.CreatedAt":                  AssertTimeToDuration(time.Second),
+				TimeType:                       AssertTimeToDuration(time.Second),
+				FloatType:                      AssertNumberWithTolerance(0.1),
+				"*assertion.checkRulesAddress": SkipAssertion,
+				"$.Customer.Name#.items[].id":  SkipAssertion,
+			},
+		},
+		{
+			name: "Test with path not existing on the type",
+			customAssertions: map[string]AssertionFunc{
+				"$.Customer.Address.ZipCode": SkipAssertion,
+			},
+			expectedErr: "rule \"$.Customer.Address.ZipCode\": no field \"ZipCode\" in assertion.checkRulesAddress",
+		},
+		{
+			name: "Test with invalid paths",
+			customAssertions: map[string]AssertionFunc{
+				"$.Customer[]":         SkipAssertion,
+				"$.Items[0].SKU":       SkipAssertion,
+				"$.CreatedAt.Location": SkipAssertion,
+				"$.Items[].SKU.Value":  SkipAssertion,
+				"$.Items[].Price#.id":  SkipAssertion,
+			},
+			expectedErr: "rule \"$.CreatedAt.Location\": time.Time is compared as a whole, it has no field \"Location\"\n" +
+				"rule \"$.Customer[]\": assertion.checkRulesCustomer is not a slice or array\n" +
+				"rule \"$.Items[0].SKU\": indexes never match, use [] instead\n" +
+				"rule \"$.Items[].Price#.id\": float64 can't hold an embedded JSON document\n" +
+				"rule \"$.Items[].SKU.Value\": string has no field \"Value\"",
+		},
+		{
+			name: "Test with type never occurring",
+			customAssertions: map[string]AssertionFunc{
+				IntType: SkipAssertion,
+			},
+			expectedErr: "rule \"int\": type never occurs in assertion.checkRulesOrder",
+		},
+		{
+			name: "Test with assertion expecting another type",
+			customAssertions: map[string]AssertionFunc{
+				"$.Customer.Name": AssertTimeToDuration(time.Second),
+				FloatType:         AssertStringWithCleanup(nil),
+			},
+			expectedErr: "rule \"$.Customer.Name\": AssertTimeToDuration expects time.Time, found string\n" +
+				"rule \"float64\": AssertStringWithCleanup expects string, found float64",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRules[checkRulesOrder](tt.customAssertions)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got:\n%v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("Expected error:\n%s\ngot:\n%v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckRulesForType_dynamic(t *testing.T) {
+	customAssertions := map[string]AssertionFunc{
+		"$[].Name":                    SkipAssertion,
+		"$[].Payload.Anything[].Goes": SkipAssertion,
+		IntType:                       SkipAssertion,
+	}
+	if err := CheckRulesForType(reflect.TypeOf([]checkRulesEvent{}), customAssertions); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestCheckRules_withNaming(t *testing.T) {
+	type customer struct {
+		CustomerID string `json:"customer_id"`
+	}
+	type order struct {
+		Customers []customer `json:"customers"`
+	}
+	customAssertions := map[string]AssertionFunc{
+		"$.customers[].customer_id": SkipAssertion,
+		"$.Customers[].CustomerID":  SkipAssertion,
+	}
+	expectedErr := "rule \"$.Customers[].CustomerID\": no field \"Customers\" in assertion.order"
+	if err := CheckRules[order](customAssertions, WithNaming(JSONTagNames)); err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error:\n%s\ngot:\n%v", expectedErr, err)
+	}
+}
+
+func TestCheckRules_genericInstantiations(t *testing.T) {
+	type cents int64
+	type payment struct {
+		Count  int64
+		Amount cents
+		Note   int
+	}
+	// the instantiations of the same shape share their code, each function keeps its own type
+	customAssertions := map[string]AssertionFunc{
+		"$.Count":  AssertNumberWithTolerance[int64](1),
+		"$.Amount": AssertNumberWithTolerance[cents](1),
+		"$.Note":   AssertNumberWithTolerance[int](1),
+	}
+	if err := CheckRules[payment](customAssertions); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	customAssertions["$.Count"] = AssertNumberWithTolerance[cents](1)
+	expectedErr := "rule \"$.Count\": AssertNumberWithTolerance expects assertion.cents, found int64"
+	if err := CheckRules[payment](customAssertions); err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error:\n%s\ngot:\n%v", expectedErr, err)
+	}
+}
+
+func TestSplitRulePath(t *testing.T) {
+	testTable := []struct {
+		name             string
+		key              string
+		expectedSegments []string
+		expectedErr      bool
+	}{
+		{
+			name:             "Test with root",
+			key:              "$",
+			expectedSegments: []string{},
+		},
+		{
+			name:             "Test with nested fields and slices",
+			key:              "$.Items[][].SKU",
+			expectedSegments: []string{"Items", "[]", "[]", "SKU"},
+		},
+		{
+			name:             "Test with embedded document",
+			key:              "$.Payload#.items[].id",
+			expectedSegments: []string{"Payload", "#", "items", "[]", "id"},
+		},
+		{
+			name:        "Test with empty field name",
+			key:         "$..SKU",
+			expectedErr: true,
+		},
+		{
+			name:        "Test with type key",
+			key:         "string",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := splitRulePath(tt.key)
+			if (err != nil) != tt.expectedErr {
+				t.Errorf("Expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if !tt.expectedErr && !reflect.DeepEqual(segments, tt.expectedSegments) {
+				t.Errorf("Expected segments: %v, got: %v", tt.expectedSegments, segments)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathField is a field of the generated paths variable
+type pathField struct {
+	name string
+	path string
+}
+
+// generate parses and type checks the package in dir and returns the source declaring the paths of the types
+// tag is the struct tag naming the fields of the paths, Go field names are used if empty
+func generate(dir string, typeNames []string, tag string) ([]byte, error) {
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by assertpaths; DO NOT EDIT.\n\npackage %s\n", pkg.Name())
+	for _, typeName := range typeNames {
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s not found in package %s", typeName, pkg.Name())
+		}
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("type %s is not a struct", typeName)
+		}
+
+		fields := collectPaths(obj.Type(), tag)
+		fmt.Fprintf(&buf, "\n// %sPaths holds the custom assertion paths of %s.\n", typeName, typeName)
+		fmt.Fprintf(&buf, "var %sPaths = struct {\n", typeName)
+		for _, field := range fields {
+			fmt.Fprintf(&buf, "%s string\n", field.name)
+		}
+		buf.WriteString("}{\n")
+		for _, field := range fields {
+			fmt.Fprintf(&buf, "%s: %s,\n", field.name, strconv.Quote(field.path))
+		}
+		buf.WriteString("}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// loadPackage parses the non test go files in dir and type checks them
+// type errors are ignored so a package with unresolved imports still generates the paths of its own types
+func loadPackage(dir string) (*types.Package, error) {
+	fset := token.NewFileSet()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	files := []*ast.File{}
+	for _, match := range matches {
+		if strings.HasSuffix(match, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(match)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, match, src, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no go files found in %s", dir)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+	return pkg, nil
+}
+
+// pathName returns the name of the field in paths, the name in the struct tag if any, else the Go field name
+func pathName(field *types.Var, structTag string, tag string) string {
+	if tag == "" {
+		return field.Name()
+	}
+	name, _, _ := strings.Cut(reflect.StructTag(structTag).Get(tag), ",")
+	if name == "" || name == "-" {
+		return field.Name()
+	}
+	return name
+}
+
+// collectPaths returns the paths of typ in the order of its fields, the same paths assertWithPaths produces
+// the name of each path joins its field names, slice and array elements are named Elem
+func collectPaths(typ types.Type, tag string) []pathField {
+	fields := []pathField{{name: "Root", path: "$"}}
+	used := map[string]bool{"Root": true}
+	add := func(name string, path string) {
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", name, i)
+		}
+		used[unique] = true
+		fields = append(fields, pathField{name: unique, path: path})
+	}
+
+	// walking stores the named types being walked to stop on recursive types
+	walking := map[types.Type]bool{}
+	var walk, walkElem func(typ types.Type, name string, path string)
+	walk = func(typ types.Type, name string, path string) {
+		if walking[typ] {
+			return
+		}
+		if named, ok := typ.(*types.Named); ok {
+			if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+				// time.Time is compared as a whole
+				return
+			}
+			walking[typ] = true
+			defer delete(walking, typ)
+		}
+
+		switch underlying := typ.Underlying().(type) {
+		case *types.Pointer:
+			walk(underlying.Elem(), name, path)
+		case *types.Slice:
+			walkElem(underlying.Elem(), name, path)
+		case *types.Array:
+			walkElem(underlying.Elem(), name, path)
+		case *types.Struct:
+			for i := 0; i < underlying.NumFields(); i++ {
+				field := underlying.Field(i)
+				if !field.Exported() {
+					continue
+				}
+				fieldPath := path + "." + pathName(field, underlying.Tag(i), tag)
+				add(name+field.Name(), fieldPath)
+				walk(field.Type(), name+field.Name(), fieldPath)
+			}
+		}
+	}
+	// walkElem adds the path of the elements of a slice or array, the fields of the elements keep the slice name
+	walkElem = func(elem types.Type, name string, path string) {
+		add(name+"Elem", path+"[]")
+		switch elem.Underlying().(type) {
+		case *types.Slice, *types.Array:
+			walk(elem, name+"Elem", path+"[]")
+		default:
+			walk(elem, name, path+"[]")
+		}
+	}
+	walk(typ, "", "$")
+	return fields
+}
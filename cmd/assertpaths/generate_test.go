@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	expected, err := os.ReadFile("testdata/shop/order_paths.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := generate("testdata/shop", []string{"Order", "Address"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("Expected source:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestGenerate_jsonTags(t *testing.T) {
+	actual, err := generate("testdata/shop", []string{"Customer"}, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`Name:          "$.name",`,
+		`Address:       "$.address",`,
+		`AddressZip:    "$.address.zip_code",`,
+		`AddressStreet: "$.address.Street",`,
+	} {
+		if !strings.Contains(string(actual), expected) {
+			t.Errorf("Expected source to contain:\n%s\ngot:\n%s", expected, actual)
+		}
+	}
+}
+
+func TestGenerate_errors(t *testing.T) {
+	testTable := []struct {
+		name          string
+		dir           string
+		typeNames     []string
+		expectedError string
+	}{
+		{
+			name:          "Test with missing type",
+			dir:           "testdata/shop",
+			typeNames:     []string{"Invoice"},
+			expectedError: "type Invoice not found in package shop",
+		},
+		{
+			name:          "Test with non struct type",
+			dir:           "testdata/shop",
+			typeNames:     []string{"Status"},
+			expectedError: "type Status is not a struct",
+		},
+		{
+			name:          "Test with directory without go files",
+			dir:           "testdata",
+			typeNames:     []string{"Order"},
+			expectedError: "no go files found in testdata",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate(tt.dir, tt.typeNames, "")
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error: %s, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Command assertpaths generates the custom assertion paths of struct types,
+// as an alternative to assertion.PathOf resolved at compile time.
+//
+// For every type given, it emits a variable named <Type>Paths holding the paths walked by assertion.Assert,
+// e.g. OrderPaths.CustomerAddressZip holds "$.Customer.Address.Zip" and OrderPaths.ItemsSKU holds "$.Items[].SKU".
+//
+// Usage:
+//
+//	//go:generate go run github.com/AndrewHany/assertion/cmd/assertpaths -type=Order,Invoice
+//
+// With -tag=json the paths are named by the json tags of the fields, matching assertion.WithNaming(assertion.JSONTagNames),
+// the names of the variable fields keep the Go field names.
+//
+// It parses and type checks the package offline with go/parser and go/types.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("assertpaths: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct type names, required")
+	output := flag.String("output", "", "output file name, default <type>_paths.go of the first type")
+	tag := flag.String("tag", "", "struct tag naming the fields of the paths, e.g. json, default Go field names")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: assertpaths -type=T[,T...] [-tag=json] [-output=file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	src, err := generate(dir, types, *tag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_paths.go"
+	}
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,36 @@
+package shop
+
+import "time"
+
+type Address struct {
+	Zip    string `json:"zip_code,omitempty"`
+	Street string `json:"-"`
+}
+
+type Customer struct {
+	Name    string   `json:"name"`
+	Address *Address `json:"address"`
+}
+
+type Item struct {
+	SKU   string
+	Price float64
+}
+
+type Category struct {
+	Name   string
+	Parent *Category
+}
+
+type Order struct {
+	ID        int
+	Customer  Customer
+	Items     []Item
+	Matrix    [2][]int
+	Totals    map[string]float64
+	Category  Category
+	CreatedAt time.Time
+	internal  string
+}
+
+type Status int
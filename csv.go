@@ -0,0 +1,280 @@
+package assertion
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/smarty/assertions"
+)
+
+// CSVOptions configures how AssertCSV parses and matches the rows of the CSV documents
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if zero
+	Comma rune
+	// NoHeader treats the first record as a row, columns are then named by their position from 1, e.g. "2"
+	NoHeader bool
+	// KeyColumn matches the rows by their value in the column instead of their position
+	KeyColumn string
+	// IgnoreOrder matches the rows regardless of their order, rows with the same values are matched first,
+	// then rows matching with the column rules, the remaining rows are compared by position
+	IgnoreOrder bool
+	// Columns are the custom assertions of the columns, e.g. "amount": AssertFloat64WithTolerance(0.01)
+	Columns map[string]AssertionFunc
+}
+
+// csvTable is a parsed CSV document, rows are numbered by their record in the document from 1, the header included
+type csvTable struct {
+	columns []string
+	rows    []csvRow
+}
+
+// csvRow is a row of a csvTable with its values keyed by column
+type csvRow struct {
+	number int
+	values map[string]string
+}
+
+// AssertCSV compares the actual and expected CSV documents using the custom assertions of the columns
+// and returns the result and message listing every difference, e.g. row 12, column "amount" with both values.
+// rows are compared by position unless KeyColumn or IgnoreOrder is set, columns are matched by name whatever their order.
+// values are strings, the number, time and string helpers accept them, see AssertTimeWithLayout for other date formats.
+// Example usage:
+//
+//	match, message := AssertCSV(actual, expected, CSVOptions{
+//		KeyColumn: "id",
+//		Columns: map[string]AssertionFunc{
+//			"amount": AssertFloat64WithTolerance(0.01),
+//			"date":   AssertTimeWithLayout("02/01/2006", 24*time.Hour),
+//			"name":   AssertStringWithCleanup(strings.TrimSpace),
+//		},
+//	})
+func AssertCSV(actual io.Reader, expected io.Reader, opts CSVOptions) (bool, string) {
+	actualTable, err := readCSV(actual, opts)
+	if err != nil {
+		return false, fmt.Sprintf("Actual is not a valid CSV document: %v", err)
+	}
+	expectedTable, err := readCSV(expected, opts)
+	if err != nil {
+		return false, fmt.Sprintf("Expected is not a valid CSV document: %v", err)
+	}
+
+	messages := compareColumns(actualTable.columns, expectedTable.columns)
+	if opts.KeyColumn != "" {
+		messages = append(messages, compareKeyedRows(actualTable, expectedTable, opts)...)
+	} else {
+		messages = append(messages, compareRows(actualTable, expectedTable, opts)...)
+	}
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "\n")
+	}
+	return true, ""
+}
+
+// readCSV parses the CSV document into its columns and rows
+func readCSV(r io.Reader, opts CSVOptions) (csvTable, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return csvTable{}, err
+	}
+	table := csvTable{}
+	first := 0
+	if len(records) > 0 {
+		if opts.NoHeader {
+			for i := range records[0] {
+				table.columns = append(table.columns, strconv.Itoa(i+1))
+			}
+		} else {
+			table.columns = records[0]
+			first = 1
+		}
+	}
+	if opts.KeyColumn != "" && !slices.Contains(table.columns, opts.KeyColumn) {
+		return csvTable{}, fmt.Errorf("key column %q not found", opts.KeyColumn)
+	}
+	for i, record := range records[first:] {
+		row := csvRow{number: first + i + 1, values: map[string]string{}}
+		for j, value := range record {
+			row.values[table.columns[j]] = value
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// compareColumns reports the columns found on one side only
+func compareColumns(actual []string, expected []string) []string {
+	messages := []string{}
+	for _, column := range actual {
+		if !slices.Contains(expected, column) {
+			messages = append(messages, fmt.Sprintf("column %q not found in expected", column))
+		}
+	}
+	for _, column := range expected {
+		if !slices.Contains(actual, column) {
+			messages = append(messages, fmt.Sprintf("column %q not found in actual", column))
+		}
+	}
+	return messages
+}
+
+// compareRows compares the rows by position, or regardless of their order with IgnoreOrder
+func compareRows(actual csvTable, expected csvTable, opts CSVOptions) []string {
+	actualRows, expectedRows := actual.rows, expected.rows
+	if opts.IgnoreOrder {
+		actualRows, expectedRows = withoutEqualRows(actualRows, expectedRows)
+		actualRows, expectedRows = withoutMatchingRows(actualRows, expectedRows, actual.columns, opts)
+	}
+	messages := []string{}
+	for i, row := range actualRows {
+		if i >= len(expectedRows) {
+			messages = append(messages, fmt.Sprintf("row %d not found in expected: %s", row.number, formatRow(row, actual.columns)))
+			continue
+		}
+		messages = append(messages, compareRow(row, expectedRows[i], actual.columns, opts)...)
+	}
+	for _, row := range expectedRows[min(len(actualRows), len(expectedRows)):] {
+		messages = append(messages, fmt.Sprintf("row %d of expected not found in actual: %s", row.number, formatRow(row, expected.columns)))
+	}
+	return messages
+}
+
+// compareKeyedRows compares the rows matched by their value in the key column
+func compareKeyedRows(actual csvTable, expected csvTable, opts CSVOptions) []string {
+	messages := []string{}
+	expectedRows := map[string]csvRow{}
+	for _, row := range expected.rows {
+		key := row.values[opts.KeyColumn]
+		if _, ok := expectedRows[key]; ok {
+			messages = append(messages, fmt.Sprintf("row %d of expected: duplicate %s %q", row.number, opts.KeyColumn, key))
+			continue
+		}
+		expectedRows[key] = row
+	}
+	matched := map[string]bool{}
+	for _, row := range actual.rows {
+		key := row.values[opts.KeyColumn]
+		if matched[key] {
+			messages = append(messages, fmt.Sprintf("row %d: duplicate %s %q", row.number, opts.KeyColumn, key))
+			continue
+		}
+		matched[key] = true
+		expectedRow, ok := expectedRows[key]
+		if !ok {
+			messages = append(messages, fmt.Sprintf("row %d: %s %q not found in expected", row.number, opts.KeyColumn, key))
+			continue
+		}
+		messages = append(messages, compareRow(row, expectedRow, actual.columns, opts)...)
+	}
+	for _, row := range expected.rows {
+		if key := row.values[opts.KeyColumn]; !matched[key] {
+			messages = append(messages, fmt.Sprintf("row %d of expected: %s %q not found in actual", row.number, opts.KeyColumn, key))
+			matched[key] = true
+		}
+	}
+	return messages
+}
+
+// compareRow compares the values of the columns found on both sides using the custom assertions of the columns
+func compareRow(actual csvRow, expected csvRow, columns []string, opts CSVOptions) []string {
+	messages := []string{}
+	for _, column := range columns {
+		expectedValue, ok := expected.values[column]
+		if !ok {
+			continue
+		}
+		customAssertion := opts.Columns[column]
+		if customAssertion == nil {
+			customAssertion = defaultAssertionFunc
+		}
+		// the values are strings, converted to the types expected by the helpers the same way as the values of documents
+		actualValue := documentValue(customAssertion, reflect.ValueOf(actual.values[column])).Interface()
+		if match, message := assertions.So(actualValue, assertions.SoFunc(customAssertion), documentValue(customAssertion, reflect.ValueOf(expectedValue)).Interface()); !match {
+			messages = append(messages, fmt.Sprintf("row %d, column %q\n%s", actual.number, column, message))
+		}
+	}
+	return messages
+}
+
+// withoutEqualRows removes the rows found on both sides with the same values, the remaining rows keep their order
+func withoutEqualRows(actual []csvRow, expected []csvRow) ([]csvRow, []csvRow) {
+	unmatched := map[string][]int{}
+	for i, row := range expected {
+		key := rowKey(row)
+		unmatched[key] = append(unmatched[key], i)
+	}
+	removed := map[int]bool{}
+	remaining := []csvRow{}
+	for _, row := range actual {
+		key := rowKey(row)
+		if indexes := unmatched[key]; len(indexes) > 0 {
+			removed[indexes[0]] = true
+			unmatched[key] = indexes[1:]
+			continue
+		}
+		remaining = append(remaining, row)
+	}
+	remainingExpected := []csvRow{}
+	for i, row := range expected {
+		if !removed[i] {
+			remainingExpected = append(remainingExpected, row)
+		}
+	}
+	return remaining, remainingExpected
+}
+
+// withoutMatchingRows removes the actual rows matching an expected row with the column rules, each actual row taking
+// the first expected row it matches, the remaining rows keep their order
+func withoutMatchingRows(actual []csvRow, expected []csvRow, columns []string, opts CSVOptions) ([]csvRow, []csvRow) {
+	removed := map[int]bool{}
+	remaining := []csvRow{}
+	for _, row := range actual {
+		i := slices.IndexFunc(expected, func(expectedRow csvRow) bool {
+			return !removed[expectedRow.number] && len(compareRow(row, expectedRow, columns, opts)) == 0
+		})
+		if i == -1 {
+			remaining = append(remaining, row)
+			continue
+		}
+		removed[expected[i].number] = true
+	}
+	remainingExpected := []csvRow{}
+	for _, row := range expected {
+		if !removed[row.number] {
+			remainingExpected = append(remainingExpected, row)
+		}
+	}
+	return remaining, remainingExpected
+}
+
+// rowKey returns the values of the row sorted by column, identifying rows with the same values
+func rowKey(row csvRow) string {
+	columns := make([]string, 0, len(row.values))
+	for column := range row.values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	key := strings.Builder{}
+	for _, column := range columns {
+		key.WriteString(strconv.Quote(column) + "=" + strconv.Quote(row.values[column]) + ";")
+	}
+	return key.String()
+}
+
+// formatRow formats the values of the row in the order of the columns
+func formatRow(row csvRow, columns []string) string {
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, fmt.Sprintf("%s=%q", column, row.values[column]))
+	}
+	return strings.Join(values, ", ")
+}
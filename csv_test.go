@@ -0,0 +1,135 @@
+package assertion
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertCSV(t *testing.T) {
+	expected := "id,name,amount,date\n1,alice,12.50,01/02/2021\n2,bob,3.00,02/02/2021\n3,carol,7.25,03/02/2021\n"
+
+	testTable := []struct {
+		name            string
+		actual          string
+		opts            CSVOptions
+		expectedOk      bool
+		expectedMessage string
+	}{
+		{
+			name:       "Test matching documents",
+			actual:     expected,
+			expectedOk: true,
+		},
+		{
+			name:       "Test columns in another order",
+			actual:     "name,id,date,amount\nalice,1,01/02/2021,12.50\nbob,2,02/02/2021,3.00\ncarol,3,03/02/2021,7.25\n",
+			expectedOk: true,
+		},
+		{
+			name:            "Test mismatch reported by row and column",
+			actual:          "id,name,amount,date\n1,alice,12.50,01/02/2021\n2,bob,3.10,02/02/2021\n3,carol,7.25,03/02/2021\n",
+			expectedOk:      false,
+			expectedMessage: "row 3, column \"amount\"\nExpected: \"3.00\"\nActual:   \"3.10\"\n(Should equal)!\n",
+		},
+		{
+			name:   "Test column rules",
+			actual: "id,name,amount,date\n1, alice ,12.5,1/2/2021\n2,bob,3.001,2/2/2021\n3,carol,7.25,3/2/2021\n",
+			opts: CSVOptions{
+				Columns: map[string]AssertionFunc{
+					"name":   AssertStringWithCleanup(strings.TrimSpace),
+					"amount": AssertFloat64WithTolerance(0.01),
+					"date":   AssertTimeWithLayout("2/1/2006", 24*time.Hour),
+				},
+			},
+			expectedOk: true,
+		},
+		{
+			name:            "Test missing and extra columns",
+			actual:          "id,name,total,date\n1,alice,12.50,01/02/2021\n2,bob,3.00,02/02/2021\n3,carol,7.25,03/02/2021\n",
+			expectedOk:      false,
+			expectedMessage: "column \"total\" not found in expected\ncolumn \"amount\" not found in actual",
+		},
+		{
+			name:            "Test missing and extra rows",
+			actual:          "id,name,amount,date\n1,alice,12.50,01/02/2021\n",
+			expectedOk:      false,
+			expectedMessage: "row 3 of expected not found in actual: id=\"2\", name=\"bob\", amount=\"3.00\", date=\"02/02/2021\"\nrow 4 of expected not found in actual: id=\"3\", name=\"carol\", amount=\"7.25\", date=\"03/02/2021\"",
+		},
+		{
+			name:       "Test rows keyed by column",
+			actual:     "id,name,amount,date\n3,carol,7.25,03/02/2021\n1,alice,12.50,01/02/2021\n2,bob,3.00,02/02/2021\n",
+			opts:       CSVOptions{KeyColumn: "id"},
+			expectedOk: true,
+		},
+		{
+			name:       "Test rows keyed by column with differences",
+			actual:     "id,name,amount,date\n4,dave,1.00,04/02/2021\n1,alice,12.50,01/02/2021\n2,bob,3.00,02/02/2021\n2,bob,3.00,02/02/2021\n",
+			opts:       CSVOptions{KeyColumn: "id"},
+			expectedOk: false,
+			expectedMessage: "row 2: id \"4\" not found in expected\n" +
+				"row 5: duplicate id \"2\"\n" +
+				"row 4 of expected: id \"3\" not found in actual",
+		},
+		{
+			name:       "Test rows ignoring order",
+			actual:     "id,name,amount,date\n2,bob,3.00,02/02/2021\n3,carol,7.25,03/02/2021\n1,alice,12.50,01/02/2021\n",
+			opts:       CSVOptions{IgnoreOrder: true},
+			expectedOk: true,
+		},
+		{
+			name:            "Test rows ignoring order with differences",
+			actual:          "id,name,amount,date\n2,bob,3.00,02/02/2021\n3,carol,7.20,03/02/2021\n1,alice,12.50,01/02/2021\n",
+			opts:            CSVOptions{IgnoreOrder: true},
+			expectedOk:      false,
+			expectedMessage: "row 3, column \"amount\"\nExpected: \"7.25\"\nActual:   \"7.20\"\n(Should equal)!\n",
+		},
+		{
+			name:   "Test rows ignoring order with column rules",
+			actual: "id,name,amount,date\n3,carol,7.249,03/02/2021\n1,alice,12.5,01/02/2021\n2,bob,3.1,02/02/2021\n",
+			opts: CSVOptions{
+				IgnoreOrder: true,
+				Columns:     map[string]AssertionFunc{"amount": AssertFloat64WithTolerance(0.01)},
+			},
+			expectedOk:      false,
+			expectedMessage: "row 4, column \"amount\"\nExpected '3.1' to almost equal '3' (but it didn't)!",
+		},
+		{
+			name:       "Test without header and other delimiter",
+			actual:     "1;alice\n2;bob\n",
+			opts:       CSVOptions{Comma: ';', NoHeader: true, Columns: map[string]AssertionFunc{"2": SkipAssertion}},
+			expectedOk: true,
+		},
+		{
+			name:            "Test invalid document",
+			actual:          "id,name\n1,alice,extra\n",
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid CSV document: record on line 2: wrong number of fields",
+		},
+		{
+			name:            "Test missing key column",
+			actual:          expected,
+			opts:            CSVOptions{KeyColumn: "ID"},
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid CSV document: key column \"ID\" not found",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			expectedDocument := expected
+			if tt.opts.NoHeader {
+				expectedDocument = "1;alice\n2;carol\n"
+			}
+			ok, message := AssertCSV(strings.NewReader(tt.actual), strings.NewReader(expectedDocument), tt.opts)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertCSV returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertCSV returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package assertion
 
 import (
+	"encoding/json"
 	"math"
+	"math/big"
+	"reflect"
 	"time"
 
 	"github.com/agnivade/levenshtein"
@@ -9,11 +12,12 @@ import (
 )
 
 const (
-	TimeType   = "time.Time"
-	FloatType  = "float64"
-	IntType    = "int"
-	Int64Type  = "int64"
-	stringType = "string"
+	TimeType       = "time.Time"
+	FloatType      = "float64"
+	IntType        = "int"
+	Int64Type      = "int64"
+	JSONNumberType = "json.Number"
+	stringType     = "string"
 )
 
 type AssertionFunc assertions.SoFunc
@@ -25,8 +29,9 @@ func SkipAssertion(actual any, expected ...any) string {
 
 // AssertTimeToDuration is a custom assertion function that truncates time to the specified duration before comparing.
 // this function is using time.Truncate() to truncate the time to the specified duration.
+// in documents, e.g. AssertJSON or MatchSnapshot, RFC 3339 strings are parsed as times, other strings are compared as is.
 func AssertTimeToDuration(duration time.Duration) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertTimeToDuration", reflect.TypeOf(time.Time{}), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
@@ -39,41 +44,113 @@ func AssertTimeToDuration(duration time.Duration) AssertionFunc {
 			expected[0] = exp.Truncate(duration)
 		}
 		return defaultAssertionFunc(actual, expected[0])
+	})
+}
+
+// AssertTimeWithLayout is a custom assertion function that parses strings with the layout, e.g. "02/01/2006",
+// and truncates the times to the specified duration before comparing, see AssertTimeToDuration.
+// time.Time values are compared as is, strings not matching the layout are compared as strings.
+func AssertTimeWithLayout(layout string, duration time.Duration) AssertionFunc {
+	return expectsType("AssertTimeWithLayout", reflect.TypeOf(""), func(actual any, expected ...any) string {
+		if len(expected) == 0 || expected[0] == nil {
+			return "expected value is missing"
+		}
+		parse := func(value any) any {
+			if str, ok := value.(string); ok {
+				if parsed, err := time.Parse(layout, str); err == nil {
+					return parsed.Truncate(duration)
+				}
+				return str
+			}
+			if t, ok := value.(time.Time); ok {
+				return t.Truncate(duration)
+			}
+			return value
+		}
+		return defaultAssertionFunc(parse(actual), parse(expected[0]))
+	})
+}
+
+// toFloat64 returns the float of a float64 or a json.Number, e.g. the numbers of JSON documents
+func toFloat64(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case json.Number:
+		f, err := typed.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toNumber returns the number of a T or a json.Number converted to T
+// integers are converted without losing precision, json.Number values with a fraction or out of the range of T are rejected
+func toNumber[T ~int | ~int64 | ~float64 | ~float32 | ~int32](value any) (T, bool) {
+	switch typed := value.(type) {
+	case T:
+		return typed, true
+	case json.Number:
+		if kind := reflect.TypeOf(T(0)).Kind(); kind == reflect.Float32 || kind == reflect.Float64 {
+			f, err := typed.Float64()
+			return T(f), err == nil
+		}
+		number, ok := new(big.Rat).SetString(typed.String())
+		if !ok || !number.IsInt() || !number.Num().IsInt64() {
+			return 0, false
+		}
+		if i := number.Num().Int64(); int64(T(i)) == i {
+			return T(i), true
+		}
+	}
+	return 0, false
+}
+
+// toString returns the string of a string or the text of a json.Number
+func toString(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case json.Number:
+		return typed.String(), true
 	}
+	return "", false
 }
 
 // AssertFloat64ToDecimalPlaces is a custom assertion function that rounds float64 to the specified decimal places before comparing.
 // this function is rounding the float64 to the specified decimal places before comparing.
 func AssertFloat64ToDecimalPlaces(decimalPlaces int) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertFloat64ToDecimalPlaces", reflect.TypeOf(float64(0)), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
-		if act, ok := actual.(float64); ok {
+		if act, ok := toFloat64(actual); ok {
 			actual = roundFloatToDecimalPlaces(act, decimalPlaces)
 		}
-		if exp, ok := expected[0].(float64); ok {
+		if exp, ok := toFloat64(expected[0]); ok {
 			expected[0] = roundFloatToDecimalPlaces(exp, decimalPlaces)
 		}
 		return defaultAssertionFunc(actual, expected[0])
-	}
+	})
 }
 
 // AssertFloat64WithTolerance is a custom assertion function that compares float64 values with a tolerance.
 // tolerance is in format of 0.0001
 // if tolerance is 0, it will compare the float64 values as is.
 func AssertFloat64WithTolerance(tolerance float64) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertFloat64WithTolerance", reflect.TypeOf(float64(0)), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
-		if act, ok := actual.(float64); ok && tolerance > 0 {
-			if exp, ok := expected[0].(float64); ok {
-				return assertions.ShouldAlmostEqual(act, exp, tolerance)
+		if act, ok := toFloat64(actual); ok {
+			if exp, ok := toFloat64(expected[0]); ok {
+				if tolerance > 0 {
+					return assertions.ShouldAlmostEqual(act, exp, tolerance)
+				}
+				return defaultAssertionFunc(act, exp)
 			}
 		}
 		return defaultAssertionFunc(actual, expected[0])
-	}
+	})
 }
 
 func roundFloatToDecimalPlaces(num float64, decimalPlaces int) float64 {
@@ -85,17 +162,17 @@ func roundFloatToDecimalPlaces(num float64, decimalPlaces int) float64 {
 // cleanup function is a function that takes a string and returns a string after cleanup.
 // if cleanup is nil, it will compare the string as is.
 func AssertStringWithCleanup(cleanup func(string) string) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertStringWithCleanup", reflect.TypeOf(""), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
-		if act, ok := actual.(string); ok {
-			if exp, ok := expected[0].(string); ok && cleanup != nil {
+		if act, ok := toString(actual); ok {
+			if exp, ok := toString(expected[0]); ok && cleanup != nil {
 				return assertions.ShouldEqual(cleanup(act), cleanup(exp))
 			}
 		}
 		return defaultAssertionFunc(actual, expected[0])
-	}
+	})
 }
 
 // SkipAssertionIf is a custom assertion function that skips the assertion if the condition is met.
@@ -126,30 +203,33 @@ func SkipAssertionIf(condition func(actual any, expected any) bool, customAssert
 // tolerance is in format of 0.0001
 // if tolerance is 0, it will compare the value as is.
 func AssertNumberWithTolerance[T ~int | ~int64 | ~float64 | ~float32 | ~int32](tolerance T) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertNumberWithTolerance", reflect.TypeOf(tolerance), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
-		if act, ok := actual.(T); ok && tolerance > 0 {
-			if exp, ok := expected[0].(T); ok {
-				return assertions.ShouldAlmostEqual(act, exp, tolerance)
+		if act, ok := toNumber[T](actual); ok {
+			if exp, ok := toNumber[T](expected[0]); ok {
+				if tolerance > 0 {
+					return assertions.ShouldAlmostEqual(act, exp, tolerance)
+				}
+				return defaultAssertionFunc(act, exp)
 			}
 		}
 		return defaultAssertionFunc(actual, expected[0])
-	}
+	})
 }
 
 func AssertStringWithDistance(distance int) AssertionFunc {
-	return func(actual any, expected ...any) string {
+	return expectsType("AssertStringWithDistance", reflect.TypeOf(""), func(actual any, expected ...any) string {
 		if len(expected) == 0 || expected[0] == nil {
 			return "expected value is missing"
 		}
-		if act, ok := actual.(string); ok {
-			if exp, ok := expected[0].(string); ok {
+		if act, ok := toString(actual); ok {
+			if exp, ok := toString(expected[0]); ok {
 				actualDistance := levenshtein.ComputeDistance(act, exp)
 				return assertions.ShouldBeLessThanOrEqualTo(actualDistance, distance)
 			}
 		}
 		return defaultAssertionFunc(actual, expected[0])
-	}
+	})
 }
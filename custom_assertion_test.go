@@ -1,6 +1,7 @@
 package assertion
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -123,6 +124,20 @@ func TestAssertFloat64ToDecimalPlaces(t *testing.T) {
 			decimal:    8,
 			expectedOk: false,
 		},
+		{
+			name:       "Test matching json.Numbers",
+			actual:     json.Number("1.234"),
+			expected:   json.Number("1.23"),
+			decimal:    2,
+			expectedOk: true,
+		},
+		{
+			name:       "Test not matching json.Number",
+			actual:     json.Number("1.25"),
+			expected:   1.23,
+			decimal:    2,
+			expectedOk: false,
+		},
 		{
 			name:       "Test with missing expected value",
 			actual:     1.23456789,
@@ -222,6 +237,19 @@ func TestAssertFloat64WithTolerance(t *testing.T) {
 			tolerance:  0.00000001,
 			expectedOk: false,
 		},
+		{
+			name:       "Test matching json.Number with tolerance",
+			actual:     json.Number("1.2345"),
+			expected:   json.Number("1.2346"),
+			tolerance:  0.001,
+			expectedOk: true,
+		},
+		{
+			name:       "Test matching json.Number without tolerance",
+			actual:     json.Number("1.0"),
+			expected:   1.0,
+			expectedOk: true,
+		},
 		{
 			name:       "Test with missing expected value",
 			actual:     1.23456789,
@@ -431,6 +459,55 @@ func TestAssertNumberWithTolerance(t *testing.T) {
 			}
 		})
 	}
+	encodedTestTable := []struct {
+		name       string
+		actual     any
+		expected   any
+		tolerance  int64
+		expectedOk bool
+	}{
+		{
+			name:       "Test matching json.Number with tolerance",
+			actual:     json.Number("50"),
+			expected:   int64(51),
+			tolerance:  1,
+			expectedOk: true,
+		},
+		{
+			name:       "Test matching json.Numbers in other notations",
+			actual:     json.Number("50"),
+			expected:   json.Number("5e1"),
+			expectedOk: true,
+		},
+		{
+			name:       "Test numeric string",
+			actual:     "50",
+			expected:   int64(50),
+			expectedOk: false,
+		},
+		{
+			name:       "Test not matching json.Numbers beyond float64 precision",
+			actual:     json.Number("9007199254740993"),
+			expected:   json.Number("9007199254740992"),
+			expectedOk: false,
+		},
+		{
+			name:       "Test not matching fractional json.Number",
+			actual:     json.Number("50.5"),
+			expected:   int64(50),
+			tolerance:  1,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range encodedTestTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := assertions.So(tt.actual, assertions.SoFunc(AssertNumberWithTolerance[int64](tt.tolerance)), tt.expected)
+			if ok != tt.expectedOk {
+				t.Errorf("AssertNumberWithTolerance failed: %s", message)
+			}
+		})
+	}
 
 }
 
@@ -488,3 +565,62 @@ func TestAssertStringWithDistance(t *testing.T) {
 		})
 	}
 }
+
+func TestAssertTimeWithLayout(t *testing.T) {
+	testTable := []struct {
+		name       string
+		actual     any
+		expected   any
+		layout     string
+		duration   time.Duration
+		expectedOk bool
+	}{
+		{
+			name:       "Test matching dates in other formats",
+			actual:     "1/2/2021",
+			expected:   "01/02/2021",
+			layout:     "2/1/2006",
+			duration:   time.Nanosecond,
+			expectedOk: true,
+		},
+		{
+			name:       "Test not matching dates",
+			actual:     "01/02/2021",
+			expected:   "02/02/2021",
+			layout:     "02/01/2006",
+			duration:   time.Nanosecond,
+			expectedOk: false,
+		},
+		{
+			name:       "Test matching times with duration",
+			actual:     "2021-02-01 10:00:30",
+			expected:   time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC),
+			layout:     time.DateTime,
+			duration:   time.Minute,
+			expectedOk: true,
+		},
+		{
+			name:       "Test not matching layout",
+			actual:     "2021-02-01",
+			expected:   "01/02/2021",
+			layout:     "02/01/2006",
+			duration:   time.Nanosecond,
+			expectedOk: false,
+		},
+		{
+			name:       "Test with missing expected value",
+			actual:     "01/02/2021",
+			layout:     "02/01/2006",
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := assertions.So(tt.actual, assertions.SoFunc(AssertTimeWithLayout(tt.layout, tt.duration)), tt.expected)
+			if ok != tt.expectedOk {
+				t.Errorf("AssertTimeWithLayout failed: %s", message)
+			}
+		})
+	}
+}
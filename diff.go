@@ -0,0 +1,213 @@
+package assertion
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// diffRenderer renders the unified diff of the values, only following the paths leading to failures
+type diffRenderer struct {
+	config
+	failedPaths map[string]bool
+	colored     bool
+	lines       []string
+}
+
+// diffChild is a field, element or key of a value rendered in the diff
+type diffChild struct {
+	label    string
+	path     string
+	actual   reflect.Value
+	expected reflect.Value
+}
+
+// renderDiff returns the unified diff of the actual and expected values for the failed paths, see WithUnifiedDiff
+func renderDiff(cfg config, failedPaths map[string]bool, actual reflect.Value, expected reflect.Value) string {
+	d := &diffRenderer{config: cfg, failedPaths: failedPaths, colored: colorEnabled()}
+	if cfg.color != nil {
+		d.colored = *cfg.color
+	}
+	d.lines = append(d.lines, "Unified diff (- expected, + actual):")
+	d.render("", actual, expected, "$", "")
+	return strings.Join(d.lines, "\n")
+}
+
+// render renders the value at the path, label is the field name, key or index written before it
+func (d *diffRenderer) render(label string, actual reflect.Value, expected reflect.Value, path string, indent string) {
+	for actual.Kind() == reflect.Ptr && expected.Kind() == reflect.Ptr && !actual.IsNil() && !expected.IsNil() {
+		actual, expected = actual.Elem(), expected.Elem()
+	}
+	if actual.Kind() == reflect.Interface || expected.Kind() == reflect.Interface {
+		actual, expected = unwrapInterface(actual), unwrapInterface(expected)
+	}
+	children, ok := d.children(actual, expected, path)
+	if d.failedPaths[path] || !ok {
+		d.changed(label, actual, expected, indent)
+		return
+	}
+
+	d.line(" ", indent+label+actual.Type().String()+"{")
+	unchanged := 0
+	for _, child := range children {
+		if !d.hasFailure(child.path) {
+			unchanged++
+			continue
+		}
+		d.elided(unchanged, indent+formatIndent)
+		unchanged = 0
+		d.render(child.label, child.actual, child.expected, child.path, indent+formatIndent)
+	}
+	d.elided(unchanged, indent+formatIndent)
+	d.line(" ", indent+"}")
+}
+
+// children returns the fields, elements or keys of the values with their paths, the same paths the walker builds
+// ok is false if the values can't be rendered by their children, in which case they are rendered as changed
+func (d *diffRenderer) children(actual reflect.Value, expected reflect.Value, path string) ([]diffChild, bool) {
+	if !actual.IsValid() || !expected.IsValid() || actual.Kind() != expected.Kind() {
+		return nil, false
+	}
+	children := []diffChild{}
+	switch actual.Kind() {
+	case reflect.Struct:
+		if actual.Type() == reflect.TypeOf(time.Time{}) {
+			return nil, false
+		}
+		typ := actual.Type()
+		if !d.promote {
+			for i := 0; i < actual.NumField(); i++ {
+				field := typ.Field(i)
+				name := d.fieldName(field)
+				children = append(children, diffChild{name + ": ", path + "." + name, actual.Field(i), expected.FieldByName(field.Name)})
+			}
+			// the fields of expected missing in actual are rendered as removed
+			for i := 0; i < expected.NumField(); i++ {
+				field := expected.Type().Field(i)
+				if _, ok := typ.FieldByName(field.Name); !ok {
+					name := d.fieldName(field)
+					children = append(children, diffChild{name + ": ", path + "." + name, reflect.Value{}, expected.Field(i)})
+				}
+			}
+			return children, true
+		}
+		for _, field := range embeddedFields(typ) {
+			fieldPath, _ := fieldPaths(d.config, typ, field, path)
+			if isEmbeddedStruct(field) && !d.failedPaths[fieldPath] {
+				// embedded structs are rendered through their promoted fields
+				continue
+			}
+			names := fieldNames(typ, field.Index)
+			actualField, _ := lookupField(actual, names)
+			expectedField, _ := lookupField(expected, names)
+			label := strings.TrimPrefix(fieldPath, path+".") + ": "
+			children = append(children, diffChild{label, fieldPath, actualField, expectedField})
+		}
+		// the fields of expected missing in actual are rendered as removed, with the fields of a missing embedded struct
+		missing := [][]int{}
+		for _, field := range embeddedFields(expected.Type()) {
+			if hasIndexPrefix(field.Index, missing) {
+				continue
+			}
+			names := fieldNames(expected.Type(), field.Index)
+			if _, ok := lookupField(actual, names); !ok {
+				missing = append(missing, field.Index)
+				fieldPath, _ := fieldPaths(d.config, expected.Type(), field, path)
+				expectedField, _ := lookupField(expected, names)
+				children = append(children, diffChild{strings.TrimPrefix(fieldPath, path+".") + ": ", fieldPath, reflect.Value{}, expectedField})
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < actual.Len() && i < expected.Len(); i++ {
+			children = append(children, diffChild{fmt.Sprintf("[%d]: ", i), fmt.Sprintf("%s[%d]", path, i), actual.Index(i), expected.Index(i)})
+		}
+	case reflect.Map:
+		// the keys found on either side, the keys missing in actual are rendered as removed
+		keys := sortedMapKeys(actual)
+		for _, key := range sortedMapKeys(expected) {
+			if !actual.MapIndex(key).IsValid() {
+				keys = append(keys, key)
+			}
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			return compareValues(keys[i], keys[j]) < 0
+		})
+		for _, key := range keys {
+			children = append(children, diffChild{formatValue(key) + ": ", fmt.Sprintf("%s.%v", path, key.Interface()), actual.MapIndex(key), expected.MapIndex(key)})
+		}
+	default:
+		return nil, false
+	}
+	return children, true
+}
+
+// changed renders the expected value marked with - and the actual value marked with +, missing values are omitted
+func (d *diffRenderer) changed(label string, actual reflect.Value, expected reflect.Value, indent string) {
+	if expected.IsValid() {
+		d.value("-", label, expected, indent)
+	}
+	if actual.IsValid() {
+		d.value("+", label, actual, indent)
+	}
+}
+
+// value renders the formatted value, marking every line with the marker
+func (d *diffRenderer) value(marker string, label string, value reflect.Value, indent string) {
+	formatted := (&valueFormatter{visiting: map[uintptr]bool{}}).format(value, indent)
+	for i, line := range strings.Split(formatted, "\n") {
+		if i == 0 {
+			line = indent + label + line
+		}
+		d.line(marker, line)
+	}
+}
+
+// elided renders the number of unchanged values skipped, if any
+func (d *diffRenderer) elided(unchanged int, indent string) {
+	if unchanged > 0 {
+		d.line(" ", fmt.Sprintf("%s... %d unchanged", indent, unchanged))
+	}
+}
+
+// line adds a line with the marker, colored if enabled
+func (d *diffRenderer) line(marker string, content string) {
+	line := marker + " " + content
+	if d.colored && marker == "-" {
+		line = colorRed + line + colorReset
+	}
+	if d.colored && marker == "+" {
+		line = colorGreen + line + colorReset
+	}
+	d.lines = append(d.lines, line)
+}
+
+// hasFailure checks if the path or any path below it failed
+func (d *diffRenderer) hasFailure(path string) bool {
+	for failedPath := range d.failedPaths {
+		if failedPath == path || strings.HasPrefix(failedPath, path+".") || strings.HasPrefix(failedPath, path+"[") || strings.HasPrefix(failedPath, path+"#") {
+			return true
+		}
+	}
+	return false
+}
+
+// colorEnabled checks if colors can be written: stdout is a terminal, NO_COLOR is not set and not running in CI
+func colorEnabled() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if os.Getenv("CI") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
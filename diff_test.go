@@ -0,0 +1,178 @@
+package assertion
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAssert_withUnifiedDiff(t *testing.T) {
+	type item struct {
+		SKU   string
+		Price float64
+	}
+	type order struct {
+		ID       int
+		Name     string
+		Customer *checkRulesCustomer
+		Items    []item
+		Tags     map[string]string
+	}
+	actual := order{
+		ID:       1,
+		Name:     "a",
+		Customer: &checkRulesCustomer{Name: "a", Address: &checkRulesAddress{Zip: "1", Street: "a"}},
+		Items:    []item{{SKU: "a", Price: 1}, {SKU: "b", Price: 2}, {SKU: "c", Price: 3}},
+		Tags:     map[string]string{"a": "1", "b": "2"},
+	}
+	expected := order{
+		ID:       1,
+		Name:     "a",
+		Customer: &checkRulesCustomer{Name: "a", Address: &checkRulesAddress{Zip: "2", Street: "a"}},
+		Items:    []item{{SKU: "a", Price: 1}, {SKU: "b", Price: 5}, {SKU: "c", Price: 3}},
+		Tags:     map[string]string{"a": "1", "c": "2"},
+	}
+
+	testTable := []struct {
+		name         string
+		actual       any
+		expected     any
+		opts         []Option
+		expectedDiff string
+	}{
+		{
+			name:     "Test with nested failures",
+			actual:   actual,
+			expected: expected,
+			opts:     []Option{WithUnifiedDiff(), WithColor(false)},
+			expectedDiff: `Unified diff (- expected, + actual):
+  assertion.order{
+    ... 2 unchanged
+    Customer: assertion.checkRulesCustomer{
+      ... 1 unchanged
+      Address: assertion.checkRulesAddress{
+-       Zip: "2"
++       Zip: "1"
+        ... 1 unchanged
+      }
+    }
+    Items: []assertion.item{
+      ... 1 unchanged
+      [1]: assertion.item{
+        ... 1 unchanged
+-       Price: 5
++       Price: 2
+      }
+      ... 1 unchanged
+    }
+    Tags: map[string]string{
+      ... 1 unchanged
++     "b": "2"
+-     "c": "2"
+    }
+  }`,
+		},
+		{
+			name:     "Test with keys missing in actual",
+			actual:   map[string]int{"a": 1},
+			expected: map[string]int{"a": 1, "b": 2},
+			opts:     []Option{WithUnifiedDiff(), WithColor(false)},
+			expectedDiff: `Unified diff (- expected, + actual):
+  map[string]int{
+    ... 1 unchanged
+-   "b": 2
+  }`,
+		},
+		{
+			name:     "Test with fields missing in actual",
+			actual:   map[string]any{"a": struct{ ID int }{ID: 1}},
+			expected: map[string]any{"a": struct{ ID, Count int }{ID: 1, Count: 2}},
+			opts:     []Option{WithUnifiedDiff(), WithColor(false)},
+			expectedDiff: `Unified diff (- expected, + actual):
+  map[string]interface {}{
+    "a": struct { ID int }{
+      ... 1 unchanged
+-     Count: 2
+    }
+  }`,
+		},
+		{
+			name:     "Test with multi-line values and colors",
+			actual:   []order{{ID: 1}},
+			expected: []order{{ID: 1}, {ID: 2}},
+			opts:     []Option{WithUnifiedDiff(), WithColor(true)},
+			expectedDiff: "Unified diff (- expected, + actual):\n" +
+				"\x1b[31m- []assertion.order{\x1b[0m\n" +
+				"\x1b[31m-   assertion.order{\x1b[0m\n" +
+				"\x1b[31m-     ID: 1,\x1b[0m\n" +
+				"\x1b[31m-     Name: \"\",\x1b[0m\n" +
+				"\x1b[31m-     Customer: (*assertion.checkRulesCustomer)(nil),\x1b[0m\n" +
+				"\x1b[31m-     Items: []assertion.item(nil),\x1b[0m\n" +
+				"\x1b[31m-     Tags: map[string]string(nil),\x1b[0m\n" +
+				"\x1b[31m-   },\x1b[0m\n" +
+				"\x1b[31m-   assertion.order{\x1b[0m\n" +
+				"\x1b[31m-     ID: 2,\x1b[0m\n" +
+				"\x1b[31m-     Name: \"\",\x1b[0m\n" +
+				"\x1b[31m-     Customer: (*assertion.checkRulesCustomer)(nil),\x1b[0m\n" +
+				"\x1b[31m-     Items: []assertion.item(nil),\x1b[0m\n" +
+				"\x1b[31m-     Tags: map[string]string(nil),\x1b[0m\n" +
+				"\x1b[31m-   },\x1b[0m\n" +
+				"\x1b[31m- }\x1b[0m\n" +
+				"\x1b[32m+ []assertion.order{\x1b[0m\n" +
+				"\x1b[32m+   assertion.order{\x1b[0m\n" +
+				"\x1b[32m+     ID: 1,\x1b[0m\n" +
+				"\x1b[32m+     Name: \"\",\x1b[0m\n" +
+				"\x1b[32m+     Customer: (*assertion.checkRulesCustomer)(nil),\x1b[0m\n" +
+				"\x1b[32m+     Items: []assertion.item(nil),\x1b[0m\n" +
+				"\x1b[32m+     Tags: map[string]string(nil),\x1b[0m\n" +
+				"\x1b[32m+   },\x1b[0m\n" +
+				"\x1b[32m+ }\x1b[0m",
+		},
+		{
+			name:     "Test without failures",
+			actual:   actual,
+			expected: actual,
+			opts:     []Option{WithUnifiedDiff()},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			_, message := Assert(tt.actual, tt.expected, nil, tt.opts...)
+			diff := ""
+			if index := strings.Index(message, "Unified diff"); index != -1 {
+				diff = message[index:]
+			}
+			if diff != tt.expectedDiff {
+				t.Errorf("Expected diff:\n%s\ngot:\n%s", tt.expectedDiff, diff)
+			}
+		})
+	}
+}
+
+func TestAssert_withUnifiedDiffAndPromotedFields(t *testing.T) {
+	actual := promotedOrder{BaseModel: BaseModel{ID: 1}, ID: "a"}
+	expected := promotedOrder{BaseModel: BaseModel{ID: 2, Tags: []string{"a"}}, ID: "a"}
+	expectedDiff := `Unified diff (- expected, + actual):
+  assertion.promotedOrder{
+-   BaseModel.ID: 2
++   BaseModel.ID: 1
+    ... 1 unchanged
+-   Tags: []string{"a"}
++   Tags: []string(nil)
+    ... 3 unchanged
+  }`
+	_, message := Assert(actual, expected, nil, WithPromotedFields(), WithUnifiedDiff(), WithColor(false))
+	// remove anything before the diff
+	diff := regexp.MustCompile(`(?s)^.*Unified diff`).ReplaceAllString(message, "Unified diff")
+	if diff != expectedDiff {
+		t.Errorf("Expected diff:\n%s\ngot:\n%s", expectedDiff, diff)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if colorEnabled() {
+		t.Error("Expected colors to be disabled with NO_COLOR")
+	}
+}
@@ -0,0 +1,69 @@
+package assertion
+
+import (
+	"reflect"
+)
+
+// EmbeddedJSON is a custom assertion comparing strings or byte slices holding serialized JSON as the documents they hold,
+// so whitespace and key order don't matter. used as a rule, e.g. "$.Payload": EmbeddedJSON, both documents are walked
+// with the same engine and their paths continue after #, e.g. $.Payload#.items[0].id,
+// so rules can reach inside the embedded documents, e.g. "$.Payload#.items[].id": SkipAssertion.
+// called directly, the documents are compared without rules, see AssertJSON
+func EmbeddedJSON(actual any, expected ...any) string {
+	if len(expected) == 0 || expected[0] == nil {
+		return "expected value is missing"
+	}
+	actualData, actualOk := bytesOf(reflect.ValueOf(actual))
+	expectedData, expectedOk := bytesOf(reflect.ValueOf(expected[0]))
+	if !actualOk || !expectedOk {
+		return defaultAssertionFunc(actual, expected[0])
+	}
+	if len(actualData) == 0 && len(expectedData) == 0 {
+		return ""
+	}
+	if match, message := AssertJSON(actualData, expectedData, nil); !match {
+		return message
+	}
+	return ""
+}
+
+// isEmbeddedJSON checks if the custom assertion is EmbeddedJSON, walked by the walker instead of being called
+func isEmbeddedJSON(customAssertion AssertionFunc) bool {
+	return reflect.ValueOf(customAssertion).Pointer() == reflect.ValueOf(EmbeddedJSON).Pointer()
+}
+
+// walkEmbeddedJSON decodes the JSON documents held by the values and walks them at the path followed by #
+func (w *walker) walkEmbeddedJSON(actual reflect.Value, expected reflect.Value, path string) (bool, string) {
+	actualData, actualOk := bytesOf(actual)
+	expectedData, expectedOk := bytesOf(expected)
+	if !actualOk || !expectedOk {
+		return w.assertValue(path, defaultAssertionFunc, actual, expected)
+	}
+	if len(actualData) == 0 && len(expectedData) == 0 {
+		return true, ""
+	}
+	actualDocument, err := decodeJSON(actualData)
+	if err != nil {
+		return false, w.fail(path, "Path: %s\nActual is not a valid JSON document: %v\nActual: %s", path, err, formatValue(actual))
+	}
+	expectedDocument, err := decodeJSON(expectedData)
+	if err != nil {
+		return false, w.fail(path, "Path: %s\nExpected is not a valid JSON document: %v\nExpected: %s", path, err, formatValue(expected))
+	}
+	// the values of the documents are compared as decoded documents, see config.documents
+	documents := w.documents
+	w.documents = true
+	defer func() { w.documents = documents }()
+	return w.walk(reflect.ValueOf(actualDocument), reflect.ValueOf(expectedDocument), path+"#")
+}
+
+// bytesOf returns the bytes of a string or byte slice value, e.g. a json.RawMessage
+func bytesOf(value reflect.Value) ([]byte, bool) {
+	switch {
+	case value.Kind() == reflect.String:
+		return []byte(value.String()), true
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return value.Bytes(), true
+	}
+	return nil, false
+}
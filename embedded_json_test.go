@@ -0,0 +1,177 @@
+package assertion
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/smarty/assertions"
+)
+
+type embeddedJSONEvent struct {
+	Name    string
+	Payload string
+	Raw     []byte
+	Message json.RawMessage
+}
+
+func TestAssert_embeddedJSON(t *testing.T) {
+	expected := embeddedJSONEvent{
+		Name:    "created",
+		Payload: `{"id": 1, "items": [{"id": "a", "qty": 1}]}`,
+		Raw:     []byte(`{"a": true}`),
+		Message: json.RawMessage(`[1, 2]`),
+	}
+
+	testTable := []struct {
+		name             string
+		actual           embeddedJSONEvent
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test documents with other whitespace and key order",
+			actual: embeddedJSONEvent{
+				Name:    "created",
+				Payload: "{\n  \"items\": [{\"qty\": 1.0, \"id\": \"a\"}],\n  \"id\": 1\n}",
+				Raw:     []byte(` { "a" : true } `),
+				Message: json.RawMessage(`[1,2]`),
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.Payload": EmbeddedJSON,
+				"$.Raw":     EmbeddedJSON,
+				"$.Message": EmbeddedJSON,
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test documents without the rule",
+			actual: embeddedJSONEvent{
+				Name:    "created",
+				Payload: `{"items": [{"id": "a", "qty": 1}], "id": 1}`,
+				Raw:     []byte(`{"a": true}`),
+				Message: json.RawMessage(`[1, 2]`),
+			},
+			expectedOk: false,
+			expectedMessage: "Path: $.Payload\nExpected: \"{\\\"id\\\": 1, \\\"items\\\": [{\\\"id\\\": \\\"a\\\", \\\"qty\\\": 1}]}\"\nActual:   \"{\\\"items\\\": [{\\\"id\\\": \\\"a\\\", \\\"qty\\\": 1}], \\\"id\\\": 1}\"\n(Should equal)!\n" +
+				"String diff at rune 4:\nExpected: {\"id\":·1,·\"items\":·[{\"i...\nActual:   {\"items\":·[{\"id\":·\"a\",·...\n             ^",
+		},
+		{
+			name: "Test mismatch inside the document",
+			actual: embeddedJSONEvent{
+				Name:    "created",
+				Payload: `{"id": 1, "items": [{"id": "b", "qty": 1}]}`,
+				Raw:     []byte(`{"a": true}`),
+				Message: json.RawMessage(`[1, 2]`),
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.Payload": EmbeddedJSON,
+			},
+			expectedOk:      false,
+			expectedMessage: "Path: $.Payload#.items[0].id\nExpected: \"a\"\nActual:   \"b\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test rules inside the document",
+			actual: embeddedJSONEvent{
+				Name:    "created",
+				Payload: `{"id": 2, "items": [{"id": "b", "qty": 1}]}`,
+				Raw:     []byte(`{"a": true}`),
+				Message: json.RawMessage(`[1, 2]`),
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.Payload":             EmbeddedJSON,
+				"$.Payload#.id":         SkipAssertion,
+				"$.Payload#.items[].id": SkipAssertion,
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test invalid document",
+			actual: embeddedJSONEvent{
+				Name:    "created",
+				Payload: `{"id": 1`,
+				Raw:     []byte(`{"a": true}`),
+				Message: json.RawMessage(`[1, 2]`),
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.Payload": EmbeddedJSON,
+			},
+			expectedOk:      false,
+			expectedMessage: "Path: $.Payload\nActual is not a valid JSON document: unexpected EOF\nActual: \"{\\\"id\\\": 1\"",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := Assert(tt.actual, expected, tt.customAssertions, WithStrictRules())
+			if ok != tt.expectedOk {
+				t.Fatalf("Assert returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("Assert returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestEmbeddedJSON(t *testing.T) {
+	testTable := []struct {
+		name       string
+		actual     any
+		expected   any
+		expectedOk bool
+	}{
+		{
+			name:       "Test matching strings",
+			actual:     `{"a": 1, "b": [true]}`,
+			expected:   `{"b":[true],"a":1.0}`,
+			expectedOk: true,
+		},
+		{
+			name:       "Test matching string and bytes",
+			actual:     `{"a": 1}`,
+			expected:   []byte(`{"a": 1}`),
+			expectedOk: true,
+		},
+		{
+			name:       "Test not matching documents",
+			actual:     `{"a": 1}`,
+			expected:   `{"a": 2}`,
+			expectedOk: false,
+		},
+		{
+			name:       "Test empty documents",
+			actual:     "",
+			expected:   []byte{},
+			expectedOk: true,
+		},
+		{
+			name:       "Test invalid document",
+			actual:     `{"a": 1}`,
+			expected:   `{"a":`,
+			expectedOk: false,
+		},
+		{
+			name:       "Test non string type",
+			actual:     1,
+			expected:   `1`,
+			expectedOk: false,
+		},
+		{
+			name:       "Test with missing expected value",
+			actual:     `{}`,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := assertions.So(tt.actual, assertions.SoFunc(EmbeddedJSON), tt.expected)
+			if ok != tt.expectedOk {
+				t.Errorf("EmbeddedJSON failed: %s", message)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package assertion
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+const (
+	// maxFormattedElements is the number of elements of slices, arrays and maps formatted before truncating
+	maxFormattedElements = 20
+	// maxFormattedBytes is the number of bytes of byte slices formatted in the hex view before truncating
+	maxFormattedBytes = 256
+	// maxInlineLength is the length up to which composite values without nested lines are formatted on a single line
+	maxInlineLength = 60
+	// formatIndent indents the fields and elements of nested values
+	formatIndent = "  "
+)
+
+// formatValue formats the value for failure messages, with type names and indented nested values
+// nil values are annotated with their type, e.g. (*Order)(nil), invalid values are formatted as nil,
+// byte slices are formatted as hex dumps, times as RFC3339Nano, values of named scalar types with their type, e.g. json.Number("1.5"),
+// and long collections are truncated. values of unexported fields are formatted the same as exported ones
+func formatValue(value reflect.Value) string {
+	return (&valueFormatter{visiting: map[uintptr]bool{}}).format(value, "")
+}
+
+// valueFormatter formats values, visiting holds the pointers being formatted to stop on cycles
+type valueFormatter struct {
+	visiting map[uintptr]bool
+}
+
+// format formats the value, indent is the indentation of the line the value starts on
+func (f *valueFormatter) format(value reflect.Value, indent string) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+	value = readable(value)
+	typ := value.Type()
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return "nil"
+		}
+		return f.format(value.Elem(), indent)
+	case reflect.Ptr:
+		if value.IsNil() {
+			return fmt.Sprintf("(%s)(nil)", typ)
+		}
+		if f.visiting[value.Pointer()] {
+			return fmt.Sprintf("<cycle %s>", typ)
+		}
+		f.visiting[value.Pointer()] = true
+		defer delete(f.visiting, value.Pointer())
+		if elem := value.Elem().Kind(); elem == reflect.Struct || elem == reflect.Slice || elem == reflect.Array || elem == reflect.Map {
+			return "&" + f.format(value.Elem(), indent)
+		}
+		return fmt.Sprintf("(%s)(%s)", typ, f.format(value.Elem(), indent))
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) && value.CanInterface() {
+			return fmt.Sprintf("time.Time(%s)", value.Interface().(time.Time).Format(time.RFC3339Nano))
+		}
+		items := make([]string, 0, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			items = append(items, typ.Field(i).Name+": "+f.format(value.Field(i), indent+formatIndent))
+		}
+		return f.composite(typ.String(), items, 0, indent)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return fmt.Sprintf("%s(nil)", typ)
+		}
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return f.bytes(value, indent)
+		}
+		items := []string{}
+		for i := 0; i < value.Len() && i < maxFormattedElements; i++ {
+			items = append(items, f.format(value.Index(i), indent+formatIndent))
+		}
+		return f.composite(typ.String(), items, value.Len()-len(items), indent)
+	case reflect.Map:
+		if value.IsNil() {
+			return fmt.Sprintf("%s(nil)", typ)
+		}
+		items := []string{}
+		for _, key := range sortedMapKeys(value) {
+			if len(items) == maxFormattedElements {
+				break
+			}
+			items = append(items, f.format(key, indent+formatIndent)+": "+f.format(value.MapIndex(key), indent+formatIndent))
+		}
+		return f.composite(typ.String(), items, value.Len()-len(items), indent)
+	case reflect.String:
+		if isNamedType(typ) {
+			return fmt.Sprintf("%s(%q)", typ, value.String())
+		}
+		return fmt.Sprintf("%q", value.String())
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if value.IsNil() {
+			return fmt.Sprintf("(%s)(nil)", typ)
+		}
+		return fmt.Sprintf("(%s)(%#x)", typ, value.Pointer())
+	}
+	formatted := formatScalar(value)
+	if value.CanInterface() {
+		formatted = fmt.Sprintf("%v", value.Interface())
+	}
+	if isNamedType(typ) {
+		return fmt.Sprintf("%s(%s)", typ, formatted)
+	}
+	return formatted
+}
+
+// readable returns the value readable through Interface, including the values of unexported fields, which are read
+// through their address, and makes structs and arrays addressable so their unexported fields can be read the same way
+func readable(value reflect.Value) reflect.Value {
+	if !value.CanInterface() && value.CanAddr() {
+		return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+	}
+	if kind := value.Kind(); value.CanInterface() && !value.CanAddr() && (kind == reflect.Struct || kind == reflect.Array) {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		return addressable
+	}
+	return value
+}
+
+// formatScalar formats the value of a boolean, number or complex with the accessor of its kind, the value may not be
+// readable through Interface
+func formatScalar(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Bool:
+		return fmt.Sprint(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprint(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(value.Complex())
+	}
+	return value.String()
+}
+
+// isNamedType checks if the type is declared by a package, e.g. json.Number, as opposed to predeclared types such as string
+func isNamedType(typ reflect.Type) bool {
+	return typ.PkgPath() != "" && typ.Name() != ""
+}
+
+// composite formats the items of a struct, slice, array or map, on a single line if short enough, else one per line
+// truncated is the number of items not formatted
+func (f *valueFormatter) composite(typeName string, items []string, truncated int, indent string) string {
+	if truncated > 0 {
+		items = append(items, fmt.Sprintf("... %d more", truncated))
+	}
+	inline := typeName + "{" + strings.Join(items, ", ") + "}"
+	if len(inline) <= maxInlineLength && !strings.Contains(inline, "\n") {
+		return inline
+	}
+	var b strings.Builder
+	b.WriteString(typeName + "{\n")
+	for _, item := range items {
+		b.WriteString(indent + formatIndent + item + ",\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// bytes formats a byte slice or array as a hex dump
+func (f *valueFormatter) bytes(value reflect.Value, indent string) string {
+	header := fmt.Sprintf("%s(%d bytes)", value.Type(), value.Len())
+	if value.Len() == 0 {
+		return header + "{}"
+	}
+	// only the formatted bytes are copied, named byte types, e.g. []MyByte, can't be copied into a []byte
+	data := make([]byte, min(value.Len(), maxFormattedBytes))
+	for i := range data {
+		data[i] = byte(value.Index(i).Uint())
+	}
+	truncated := value.Len() - len(data)
+	var b strings.Builder
+	b.WriteString(header + "{\n")
+	for _, line := range strings.Split(strings.TrimSuffix(hex.Dump(data), "\n"), "\n") {
+		b.WriteString(indent + formatIndent + line + "\n")
+	}
+	if truncated > 0 {
+		b.WriteString(fmt.Sprintf("%s%s... %d more bytes\n", indent, formatIndent, truncated))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package assertion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFormatValue(t *testing.T) {
+	type item struct {
+		SKU   string
+		Price float64
+	}
+	type order struct {
+		ID        int
+		Customer  *checkRulesCustomer
+		Items     []item
+		Tags      map[string]string
+		CreatedAt time.Time
+	}
+	type node struct {
+		Value int
+		Next  *node
+	}
+	type flag uint8
+	testTime, _ := time.Parse(time.RFC3339Nano, "2021-01-01T00:00:00.123456789Z")
+	number := 5
+	cycle := &node{Value: 1}
+	cycle.Next = cycle
+	long := make([]int, 25)
+
+	testTable := []struct {
+		name          string
+		value         reflect.Value
+		expectedValue string
+	}{
+		{
+			name:          "Test with invalid value",
+			value:         reflect.ValueOf(nil),
+			expectedValue: "nil",
+		},
+		{
+			name:          "Test with nil pointer",
+			value:         reflect.ValueOf((*order)(nil)),
+			expectedValue: "(*assertion.order)(nil)",
+		},
+		{
+			name:          "Test with pointer to scalar",
+			value:         reflect.ValueOf(&number),
+			expectedValue: "(*int)(5)",
+		},
+		{
+			name:          "Test with string",
+			value:         reflect.ValueOf("a\tb"),
+			expectedValue: `"a\tb"`,
+		},
+		{
+			name:          "Test with short struct",
+			value:         reflect.ValueOf(item{SKU: "a", Price: 1.5}),
+			expectedValue: `assertion.item{SKU: "a", Price: 1.5}`,
+		},
+		{
+			name:          "Test with time",
+			value:         reflect.ValueOf(testTime),
+			expectedValue: "time.Time(2021-01-01T00:00:00.123456789Z)",
+		},
+		{
+			name: "Test with nested struct",
+			value: reflect.ValueOf(&order{
+				ID:        1,
+				Customer:  &checkRulesCustomer{Name: "a"},
+				Items:     []item{{SKU: "a", Price: 1}, {SKU: "b", Price: 2}},
+				Tags:      map[string]string{"b": "2", "a": "1"},
+				CreatedAt: testTime,
+			}),
+			expectedValue: `&assertion.order{
+  ID: 1,
+  Customer: &assertion.checkRulesCustomer{
+    Name: "a",
+    Address: (*assertion.checkRulesAddress)(nil),
+  },
+  Items: []assertion.item{
+    assertion.item{SKU: "a", Price: 1},
+    assertion.item{SKU: "b", Price: 2},
+  },
+  Tags: map[string]string{"a": "1", "b": "2"},
+  CreatedAt: time.Time(2021-01-01T00:00:00.123456789Z),
+}`,
+		},
+		{
+			name:          "Test with nil slice and map",
+			value:         reflect.ValueOf(order{}),
+			expectedValue: "assertion.order{\n  ID: 0,\n  Customer: (*assertion.checkRulesCustomer)(nil),\n  Items: []assertion.item(nil),\n  Tags: map[string]string(nil),\n  CreatedAt: time.Time(0001-01-01T00:00:00Z),\n}",
+		},
+		{
+			name:          "Test with long slice",
+			value:         reflect.ValueOf(long),
+			expectedValue: "[]int{\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  0,\n  ... 5 more,\n}",
+		},
+		{
+			name:          "Test with bytes",
+			value:         reflect.ValueOf([]byte("hello")),
+			expectedValue: "[]uint8(5 bytes){\n  00000000  68 65 6c 6c 6f                                    |hello|\n}",
+		},
+		{
+			name:          "Test with named bytes",
+			value:         reflect.ValueOf([]flag{1, 2}),
+			expectedValue: "[]assertion.flag(2 bytes){\n  00000000  01 02                                             |..|\n}",
+		},
+		{
+			name:          "Test with named scalars",
+			value:         reflect.ValueOf([]any{json.Number("1.5"), 2 * time.Second}),
+			expectedValue: `[]interface {}{json.Number("1.5"), time.Duration(2s)}`,
+		},
+		{
+			name: "Test with unexported fields",
+			value: reflect.ValueOf(struct {
+				n  int
+				ok bool
+				at time.Time
+			}{n: 5, ok: true, at: testTime}),
+			expectedValue: "struct { n int; ok bool; at time.Time }{\n  n: 5,\n  ok: true,\n  at: time.Time(2021-01-01T00:00:00.123456789Z),\n}",
+		},
+		{
+			name:          "Test with unexported fields of map values",
+			value:         reflect.ValueOf(map[string]struct{ at time.Time }{"a": {at: testTime}}),
+			expectedValue: "map[string]struct { at time.Time }{\n  \"a\": struct { at time.Time }{\n    at: time.Time(2021-01-01T00:00:00.123456789Z),\n  },\n}",
+		},
+		{
+			name:          "Test with unexported field not addressable",
+			value:         reflect.ValueOf(struct{ n float64 }{n: 1.5}).Field(0),
+			expectedValue: "1.5",
+		},
+		{
+			name:          "Test with cycle",
+			value:         reflect.ValueOf(cycle),
+			expectedValue: "&assertion.node{Value: 1, Next: <cycle *assertion.node>}",
+		},
+		{
+			name:          "Test with interface",
+			value:         reflect.ValueOf([]any{nil, 1, "a"}),
+			expectedValue: `[]interface {}{nil, 1, "a"}`,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if value := formatValue(tt.value); value != tt.expectedValue {
+				t.Errorf("Expected value:\n%s\ngot:\n%s", tt.expectedValue, value)
+			}
+		})
+	}
+}
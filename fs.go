@@ -0,0 +1,202 @@
+package assertion
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/smarty/assertions"
+)
+
+var trailingSpaceRegex = regexp.MustCompile(`(?m)[ \t]+$`)
+
+// AssertFS compares the files of the actual and expected trees, e.g. os.DirFS("out") and fstest.MapFS,
+// and returns the result and message listing the missing and extra files and the files with other contents.
+// contents are compared as strings using the custom assertion of the first rule matching the file, keyed by glob:
+// a pattern with a / is matched against the slash-separated path of the file, else against its name, see path.Match.
+// the exact path takes precedence over the patterns with a /, and both over the patterns on names.
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		"*.json":        EmbeddedJSON,
+//		"*.go":          AssertStringWithCleanup(FormatGoSource),
+//		"*.png":         AssertSHA256,
+//		"gen/stamp.txt": SkipAssertion,
+//	}
+//	match, message := AssertFS(os.DirFS("out"), os.DirFS("testdata/golden"), customAssertions)
+func AssertFS(actual fs.FS, expected fs.FS, customAssertions map[string]AssertionFunc) (bool, string) {
+	actualFiles, err := listFiles(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual file tree can't be read: %v", err)
+	}
+	expectedFiles, err := listFiles(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected file tree can't be read: %v", err)
+	}
+	for key := range customAssertions {
+		if _, err := path.Match(key, ""); err != nil {
+			return false, fmt.Sprintf("Invalid rule %q: %v", key, err)
+		}
+	}
+
+	messages := []string{}
+	for _, name := range unionKeys(actualFiles, expectedFiles) {
+		actualDir, inActual := actualFiles[name]
+		expectedDir, inExpected := expectedFiles[name]
+		switch {
+		case !inExpected:
+			if !actualDir {
+				messages = append(messages, fmt.Sprintf("file %s not found in expected", name))
+			}
+		case !inActual:
+			if !expectedDir {
+				messages = append(messages, fmt.Sprintf("file %s not found in actual", name))
+			}
+		case actualDir != expectedDir:
+			messages = append(messages, fmt.Sprintf("file %s is a %s in actual and a %s in expected", name, fileKind(actualDir), fileKind(expectedDir)))
+		case !actualDir:
+			if message := compareFile(actual, expected, name, customAssertions); message != "" {
+				messages = append(messages, message)
+			}
+		}
+	}
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "\n")
+	}
+	return true, ""
+}
+
+// AssertSHA256 is a custom assertion function comparing the SHA-256 hashes of strings or byte slices,
+// reporting the sizes and hashes instead of the contents, e.g. for binary files
+func AssertSHA256(actual any, expected ...any) string {
+	if len(expected) == 0 || expected[0] == nil {
+		return "expected value is missing"
+	}
+	actualData, actualOk := bytesOf(reflect.ValueOf(actual))
+	expectedData, expectedOk := bytesOf(reflect.ValueOf(expected[0]))
+	if !actualOk || !expectedOk {
+		return defaultAssertionFunc(actual, expected[0])
+	}
+	if bytes.Equal(actualData, expectedData) {
+		return ""
+	}
+	return fmt.Sprintf("Expected: %d bytes, sha256 %x\nActual:   %d bytes, sha256 %x",
+		len(expectedData), sha256.Sum256(expectedData), len(actualData), sha256.Sum256(actualData))
+}
+
+// FormatGoSource is a cleanup for AssertStringWithCleanup formatting Go source with gofmt,
+// source which can't be parsed has its trailing whitespace and blank lines removed instead
+func FormatGoSource(source string) string {
+	if formatted, err := format.Source([]byte(source)); err == nil {
+		return string(formatted)
+	}
+	return strings.TrimRight(trailingSpaceRegex.ReplaceAllString(source, ""), "\n") + "\n"
+}
+
+// listFiles returns the slash-separated paths of the files and directories of the tree, true for directories
+func listFiles(fsys fs.FS) (map[string]bool, error) {
+	files := map[string]bool{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." {
+			files[name] = entry.IsDir()
+		}
+		return nil
+	})
+	return files, err
+}
+
+// compareFile compares the contents of the file on both sides and returns the message of the difference, if any
+func compareFile(actual fs.FS, expected fs.FS, name string, customAssertions map[string]AssertionFunc) string {
+	actualData, err := fs.ReadFile(actual, name)
+	if err != nil {
+		return fmt.Sprintf("file %s can't be read in actual: %v", name, err)
+	}
+	expectedData, err := fs.ReadFile(expected, name)
+	if err != nil {
+		return fmt.Sprintf("file %s can't be read in expected: %v", name, err)
+	}
+	return compareContents("file "+name, name, actualData, expectedData, customAssertions)
+}
+
+// compareContents compares the contents of the file using the custom assertion of its rule,
+// without a rule text is diffed and binary data is compared by hash, the message starts with the label of the file
+func compareContents(label string, name string, actualData []byte, expectedData []byte, customAssertions map[string]AssertionFunc) string {
+	if customAssertion, ok := fileRule(name, customAssertions); ok {
+		if match, message := assertions.So(string(actualData), assertions.SoFunc(customAssertion), string(expectedData)); !match {
+			return fmt.Sprintf("%s\n%s", label, message)
+		}
+		return ""
+	}
+	if bytes.Equal(actualData, expectedData) {
+		return ""
+	}
+	if !utf8.Valid(actualData) || !utf8.Valid(expectedData) {
+		return fmt.Sprintf("%s\n%s", label, AssertSHA256(actualData, expectedData))
+	}
+	if diff := stringDiff(string(actualData), string(expectedData)); diff != "" {
+		return fmt.Sprintf("%s\n%s", label, diff)
+	}
+	return fmt.Sprintf("%s\nExpected: %q\nActual:   %q", label, expectedData, actualData)
+}
+
+// fileRule returns the custom assertion of the rule matching the file, see AssertFS for the precedence
+// rules with the same precedence are tried in the order of their keys
+func fileRule(name string, customAssertions map[string]AssertionFunc) (AssertionFunc, bool) {
+	if customAssertion, ok := customAssertions[name]; ok {
+		return customAssertion, true
+	}
+	keys := make([]string, 0, len(customAssertions))
+	for key := range customAssertions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, onPath := range []bool{true, false} {
+		for _, key := range keys {
+			if strings.Contains(key, "/") != onPath {
+				continue
+			}
+			target := path.Base(name)
+			if onPath {
+				target = name
+			}
+			if ok, _ := path.Match(key, target); ok {
+				return customAssertions[key], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// unionKeys returns the keys of both maps sorted
+func unionKeys(a map[string]bool, b map[string]bool) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fileKind names the kind of file in messages
+func fileKind(dir bool) string {
+	if dir {
+		return "directory"
+	}
+	return "file"
+}
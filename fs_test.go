@@ -0,0 +1,148 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssertFS(t *testing.T) {
+	expected := fstest.MapFS{
+		"config.json":       {Data: []byte(`{"name": "app", "ports": [80, 443]}`)},
+		"main.go":           {Data: []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")},
+		"assets/logo.png":   {Data: []byte{0x89, 'P', 'N', 'G', 0xff}},
+		"gen/stamp.txt":     {Data: []byte("generated at 10:00")},
+		"docs/readme.md":    {Data: []byte("# App\n\nline 2\nline 3\n")},
+		"docs/api/index.md": {Data: []byte("api")},
+	}
+	rules := map[string]AssertionFunc{
+		"*.json":        EmbeddedJSON,
+		"*.go":          AssertStringWithCleanup(FormatGoSource),
+		"*.png":         AssertSHA256,
+		"gen/stamp.txt": SkipAssertion,
+	}
+
+	testTable := []struct {
+		name            string
+		actual          fstest.MapFS
+		rules           map[string]AssertionFunc
+		expectedOk      bool
+		expectedMessage string
+	}{
+		{
+			name: "Test matching trees with rules",
+			actual: fstest.MapFS{
+				"config.json":       {Data: []byte("{\n  \"ports\": [80, 443],\n  \"name\": \"app\"\n}")},
+				"main.go":           {Data: []byte("package main\nfunc main()  {\n    println(\"hi\")\n}")},
+				"assets/logo.png":   {Data: []byte{0x89, 'P', 'N', 'G', 0xff}},
+				"gen/stamp.txt":     {Data: []byte("generated at 11:00")},
+				"docs/readme.md":    {Data: []byte("# App\n\nline 2\nline 3\n")},
+				"docs/api/index.md": {Data: []byte("api")},
+			},
+			rules:      rules,
+			expectedOk: true,
+		},
+		{
+			name: "Test missing, extra and different files",
+			actual: fstest.MapFS{
+				"config.json":     {Data: []byte(`{"name": "app", "ports": [80, 443]}`)},
+				"main.go":         {Data: []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")},
+				"assets/logo.png": {Data: []byte{0x89, 'P', 'N', 'G', 0x00}},
+				"gen/stamp.txt":   {Data: []byte("generated at 10:00")},
+				"docs/readme.md":  {Data: []byte("# App\n\nline 2\nline three\n")},
+				"docs/extra.md":   {Data: []byte("extra")},
+				"docs/api":        {Data: []byte("api")},
+			},
+			expectedOk: false,
+			expectedMessage: "file assets/logo.png\nExpected: 5 bytes, sha256 88d68653bc6eba8184e26ce031ad2c828ea72973d7fd3863823a2704e6b41940\nActual:   5 bytes, sha256 ad91235e882292469812e16da0b8fc77075a7c6d6f8760c24be14a5c792508cf\n" +
+				"file docs/api is a file in actual and a directory in expected\n" +
+				"file docs/api/index.md not found in actual\n" +
+				"file docs/extra.md not found in expected\n" +
+				"file docs/readme.md\nString diff (- expected, + actual):\n@@ -2,4 +2,4 @@\n  \n  line·2\n- line·3\n+ line·three\n  ",
+		},
+		{
+			name: "Test rules on paths take precedence over names",
+			actual: fstest.MapFS{
+				"config.json":       {Data: []byte(`{"name": "app", "ports": [80, 443]}`)},
+				"main.go":           {Data: []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")},
+				"assets/logo.png":   {Data: []byte{0x89, 'P', 'N', 'G', 0xff}},
+				"gen/stamp.txt":     {Data: []byte("generated at 10:00")},
+				"docs/readme.md":    {Data: []byte("changed")},
+				"docs/api/index.md": {Data: []byte("changed")},
+			},
+			rules: map[string]AssertionFunc{
+				"docs/*":   SkipAssertion,
+				"*.md":     AssertStringWithDistance(0),
+				"docs/*/*": SkipAssertion,
+			},
+			expectedOk: true,
+		},
+		{
+			name:            "Test invalid rule",
+			actual:          expected,
+			rules:           map[string]AssertionFunc{"[": SkipAssertion},
+			expectedOk:      false,
+			expectedMessage: "Invalid rule \"[\": syntax error in pattern",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertFS(tt.actual, expected, tt.rules)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertFS returned %v: %s", ok, message)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("AssertFS returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestAssertFS_dirFS(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg\nvar A  =  1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules := map[string]AssertionFunc{"*.go": AssertStringWithCleanup(FormatGoSource)}
+
+	expected := fstest.MapFS{"pkg/a.go": {Data: []byte("package pkg\n\nvar A = 1\n")}}
+	if ok, message := AssertFS(os.DirFS(dir), expected, rules); !ok {
+		t.Errorf("AssertFS failed: %s", message)
+	}
+	expected = fstest.MapFS{"pkg/a.go": {Data: []byte("package pkg\n\nvar A = 2\n")}}
+	if ok, _ := AssertFS(os.DirFS(dir), expected, rules); ok {
+		t.Errorf("AssertFS should fail on a different value")
+	}
+}
+
+func TestFormatGoSource(t *testing.T) {
+	testTable := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "Test formatting valid source",
+			source:   "package a\nfunc  f( ) {return}",
+			expected: "package a\n\nfunc f() { return }\n",
+		},
+		{
+			name:     "Test cleanup of invalid source",
+			source:   "func f() {  \t\n\treturn \n\n\n",
+			expected: "func f() {\n\treturn\n",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := FormatGoSource(tt.source); actual != tt.expected {
+				t.Errorf("FormatGoSource returned %q, expected %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package assertion
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// literalPackage qualifies the helpers of this package used in Go literals, e.g. assertion.Ptr(5)
+const literalPackage = "assertion"
+
+// durationUnits are the units of time.Duration literals, from the largest
+var durationUnits = []struct {
+	name string
+	unit time.Duration
+}{
+	{"time.Hour", time.Hour},
+	{"time.Minute", time.Minute},
+	{"time.Second", time.Second},
+	{"time.Millisecond", time.Millisecond},
+	{"time.Microsecond", time.Microsecond},
+	{"time.Nanosecond", time.Nanosecond},
+}
+
+// Ptr returns a pointer to the value, used by GoLiteral for pointers to scalars, e.g. assertion.Ptr(int64(5))
+func Ptr[T any](value T) *T {
+	return &value
+}
+
+// GoLiteral returns Go source building the value, to paste the actual value of a failing test as its expected value.
+// types are named as in their package, e.g. shop.Order{...}, zero fields are left out and maps are sorted by key,
+// pointers to scalars use Ptr, times use time.Date and durations their largest exact unit, e.g. 90 * time.Second.
+// unexported fields are left out as they can't be set outside their package,
+// functions and channels are written as nil with a comment.
+// Example usage:
+//
+//	fmt.Println(GoLiteral(order))
+//	// shop.Order{
+//	//	ID:        "1",
+//	//	CreatedAt: time.Date(2021, time.January, 1, 10, 0, 0, 0, time.UTC),
+//	//	Tags:      []string{"a", "b"},
+//	//	Discount:  assertion.Ptr(0.5),
+//	// }
+func GoLiteral(value any) string {
+	source := (&literalWriter{visiting: map[uintptr]bool{}}).literal(reflect.ValueOf(value), nil, false)
+	formatted, err := format.Source([]byte("package p\n\nvar v = " + source + "\n"))
+	if err != nil {
+		return source
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(string(formatted), "package p\n\nvar v = "), "\n")
+}
+
+// literalWriter writes Go literals, visiting holds the pointers being written to stop on cycles
+type literalWriter struct {
+	visiting map[uintptr]bool
+}
+
+// literal returns the Go literal of the value assigned to the context type,
+// the literal is converted to the type of the value unless the context is the same type.
+// a nil context or an interface context needs the literal to carry its type, e.g. int64(5)
+// elide writes composite literals without their type, as allowed for the elements of slices, arrays and maps
+func (l *literalWriter) literal(value reflect.Value, context reflect.Type, elide bool) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+	typ := value.Type()
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return "nil"
+		}
+		return l.literal(value.Elem(), typ, false)
+	case reflect.Bool:
+		return l.scalar(strconv.FormatBool(value.Bool()), typ, context)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typ == reflect.TypeOf(time.Duration(0)) {
+			return l.duration(time.Duration(value.Int()), context)
+		}
+		return l.scalar(strconv.FormatInt(value.Int(), 10), typ, context)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return l.scalar(strconv.FormatUint(value.Uint(), 10), typ, context)
+	case reflect.Float32, reflect.Float64:
+		return l.float(value.Float(), typ, context)
+	case reflect.Complex64, reflect.Complex128:
+		c, bitSize := value.Complex(), typ.Bits()/2
+		return l.scalar(fmt.Sprintf("complex(%s, %s)", floatLiteral(real(c), bitSize), floatLiteral(imag(c), bitSize)), typ, context)
+	case reflect.String:
+		return l.scalar(strconv.Quote(value.String()), typ, context)
+	case reflect.Ptr:
+		return l.pointer(value, context, elide)
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			return timeLiteral(value.Interface().(time.Time))
+		}
+		fields := []string{}
+		for i := 0; i < value.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() || value.Field(i).IsZero() {
+				continue
+			}
+			fields = append(fields, field.Name+": "+l.literal(value.Field(i), field.Type, false))
+		}
+		return compositeLiteral(typ, elide, fields, true)
+	case reflect.Slice:
+		if value.IsNil() {
+			return l.nilLiteral(typ, context)
+		}
+		if typ.Elem().Kind() == reflect.Uint8 && utf8.Valid(value.Bytes()) {
+			return fmt.Sprintf("%s(%s)", typ, strconv.Quote(string(value.Bytes())))
+		}
+		return l.elements(value, elide)
+	case reflect.Array:
+		return l.elements(value, elide)
+	case reflect.Map:
+		if value.IsNil() {
+			return l.nilLiteral(typ, context)
+		}
+		items := make([]string, 0, value.Len())
+		for _, key := range sortedMapKeys(value) {
+			items = append(items, l.literal(key, typ.Key(), true)+": "+l.literal(value.MapIndex(key), typ.Elem(), true))
+		}
+		return compositeLiteral(typ, elide, items, true)
+	}
+	if value.IsNil() {
+		return fmt.Sprintf("nil /* %s */", typ)
+	}
+	return fmt.Sprintf("nil /* %s can't be written as a literal */", typ)
+}
+
+// scalar returns the literal text converted to the type, unless it is the type of the context
+// or the default type of the untyped constant, e.g. 5 is an int and "a" a string
+func (l *literalWriter) scalar(text string, typ reflect.Type, context reflect.Type) string {
+	if typ == context || (context == nil || context.Kind() == reflect.Interface) && isDefaultType(typ) {
+		return text
+	}
+	return fmt.Sprintf("%s(%s)", typ, text)
+}
+
+// float returns the literal of the float, NaN and infinities use the math package
+func (l *literalWriter) float(f float64, typ reflect.Type, context reflect.Type) string {
+	switch {
+	case math.IsNaN(f):
+		return l.nonConstant("math.NaN()", typ)
+	case math.IsInf(f, 1):
+		return l.nonConstant("math.Inf(1)", typ)
+	case math.IsInf(f, -1):
+		return l.nonConstant("math.Inf(-1)", typ)
+	}
+	return l.scalar(floatLiteral(f, typ.Bits()), typ, context)
+}
+
+// nonConstant returns the float64 expression converted to the type unless it is float64
+func (l *literalWriter) nonConstant(expression string, typ reflect.Type) string {
+	if typ == reflect.TypeOf(float64(0)) {
+		return expression
+	}
+	return fmt.Sprintf("%s(%s)", typ, expression)
+}
+
+// duration returns the duration in its largest exact unit, e.g. 90 * time.Second
+func (l *literalWriter) duration(d time.Duration, context reflect.Type) string {
+	if d == 0 {
+		return l.scalar("0", reflect.TypeOf(d), context)
+	}
+	unit := durationUnits[len(durationUnits)-1]
+	for _, candidate := range durationUnits {
+		if d%candidate.unit == 0 {
+			unit = candidate
+			break
+		}
+	}
+	if d == unit.unit {
+		return unit.name
+	}
+	return fmt.Sprintf("%d * %s", d/unit.unit, unit.name)
+}
+
+// pointer returns the literal of the pointer, &T{...} for composite values, written {...} when elided, else Ptr(value)
+func (l *literalWriter) pointer(value reflect.Value, context reflect.Type, elide bool) string {
+	typ := value.Type()
+	if value.IsNil() {
+		return l.nilLiteral(typ, context)
+	}
+	if l.visiting[value.Pointer()] {
+		return fmt.Sprintf("nil /* cycle %s */", typ)
+	}
+	l.visiting[value.Pointer()] = true
+	defer delete(l.visiting, value.Pointer())
+
+	if isCompositeLiteral(typ.Elem()) {
+		if elide && typ == context {
+			return l.literal(value.Elem(), typ.Elem(), true)
+		}
+		return "&" + l.literal(value.Elem(), typ.Elem(), false)
+	}
+	return fmt.Sprintf("%s.Ptr(%s)", literalPackage, l.literal(value.Elem(), nil, false))
+}
+
+// elements returns the composite literal of the slice or array, on a single line unless the elements are composite
+func (l *literalWriter) elements(value reflect.Value, elide bool) string {
+	typ := value.Type()
+	items := make([]string, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		items = append(items, l.literal(value.Index(i), typ.Elem(), true))
+	}
+	return compositeLiteral(typ, elide, items, isCompositeLiteral(derefType(typ.Elem())))
+}
+
+// nilLiteral returns nil, converted to the type unless it is the type of the context, e.g. (*int)(nil)
+func (l *literalWriter) nilLiteral(typ reflect.Type, context reflect.Type) string {
+	if typ == context {
+		return "nil"
+	}
+	if typ.Kind() == reflect.Ptr {
+		return fmt.Sprintf("(%s)(nil)", typ)
+	}
+	return fmt.Sprintf("%s(nil)", typ)
+}
+
+// compositeLiteral returns the composite literal of the type with the items, the type is left out when elided.
+// multiline writes one item per line, formatted by go/format
+func compositeLiteral(typ reflect.Type, elide bool, items []string, multiline bool) string {
+	prefix := typ.String()
+	if elide {
+		prefix = ""
+	}
+	if len(items) == 0 {
+		return prefix + "{}"
+	}
+	if !multiline {
+		return prefix + "{" + strings.Join(items, ", ") + "}"
+	}
+	return prefix + "{\n" + strings.Join(items, ",\n") + ",\n}"
+}
+
+// timeLiteral returns the time.Date call building the time, in its location when it is UTC, Local or a fixed zone
+func timeLiteral(t time.Time) string {
+	location := "time.UTC"
+	switch t.Location() {
+	case time.UTC:
+	case time.Local:
+		location = "time.Local"
+	default:
+		name, offset := t.Zone()
+		location = fmt.Sprintf("time.FixedZone(%q, %d)", name, offset)
+	}
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, %s)",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+// floatLiteral formats the float with a decimal point, so it stays a float constant, e.g. 1.0 instead of 1
+func floatLiteral(f float64, bitSize int) string {
+	text := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
+
+// isDefaultType checks if the type is the default type of untyped constants: bool, int, float64, complex128 or string
+func isDefaultType(typ reflect.Type) bool {
+	switch typ {
+	case reflect.TypeOf(false), reflect.TypeOf(0), reflect.TypeOf(0.0), reflect.TypeOf(complex128(0)), reflect.TypeOf(""):
+		return true
+	}
+	return false
+}
+
+// isCompositeLiteral checks if values of the type are written as composite literals, times are written with time.Date
+func isCompositeLiteral(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Struct:
+		return typ != reflect.TypeOf(time.Time{})
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
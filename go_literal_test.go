@@ -0,0 +1,177 @@
+package assertion
+
+import (
+	"go/parser"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type literalStatus string
+
+type literalItem struct {
+	SKU      string
+	Quantity *int
+	Price    float64
+}
+
+type literalOrder struct {
+	ID        int64
+	Status    literalStatus
+	CreatedAt time.Time
+	Timeout   time.Duration
+	Items     []literalItem
+	Refs      []*literalItem
+	Tags      map[string]int
+	Extra     any
+	Note      *string
+	internal  string
+}
+
+func TestGoLiteral(t *testing.T) {
+	testTable := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "Test nil",
+			value:    nil,
+			expected: "nil",
+		},
+		{
+			name:     "Test default types",
+			value:    []any{1, 1.0, 1.5, "a", true, complex(1, 2)},
+			expected: `[]interface{}{1, 1.0, 1.5, "a", true, complex(1.0, 2.0)}`,
+		},
+		{
+			name:     "Test converted types",
+			value:    []any{int64(1), float32(0.1), uint8(2), literalStatus("paid")},
+			expected: `[]interface{}{int64(1), float32(0.1), uint8(2), assertion.literalStatus("paid")}`,
+		},
+		{
+			name:     "Test special floats",
+			value:    []any{math.NaN(), math.Inf(-1), float32(math.Inf(1))},
+			expected: `[]interface{}{math.NaN(), math.Inf(-1), float32(math.Inf(1))}`,
+		},
+		{
+			name:     "Test durations",
+			value:    []time.Duration{0, time.Hour, 90 * time.Second, 1500 * time.Microsecond, 7},
+			expected: `[]time.Duration{0, time.Hour, 90 * time.Second, 1500 * time.Microsecond, 7 * time.Nanosecond}`,
+		},
+		{
+			name:     "Test times",
+			value:    []time.Time{time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC), time.Date(2021, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))},
+			expected: `[]time.Time{time.Date(2021, time.January, 2, 3, 4, 5, 6, time.UTC), time.Date(2021, time.January, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))}`,
+		},
+		{
+			name:     "Test pointers to scalars",
+			value:    []any{Ptr(5), Ptr(int64(5)), Ptr(Ptr("a")), (*int)(nil)},
+			expected: `[]interface{}{assertion.Ptr(5), assertion.Ptr(int64(5)), assertion.Ptr(assertion.Ptr("a")), (*int)(nil)}`,
+		},
+		{
+			name:     "Test bytes",
+			value:    map[string][]byte{"text": []byte("a\nb"), "binary": {0xff, 0}, "nil": nil},
+			expected: "map[string][]uint8{\n\t\"binary\": {255, 0},\n\t\"nil\":    nil,\n\t\"text\":   []uint8(\"a\\nb\"),\n}",
+		},
+		{
+			name:     "Test nil values in interface",
+			value:    []any{[]int(nil), map[string]int(nil)},
+			expected: `[]interface{}{[]int(nil), map[string]int(nil)}`,
+		},
+		{
+			name:     "Test empty struct",
+			value:    literalOrder{internal: "a"},
+			expected: "assertion.literalOrder{}",
+		},
+		{
+			name: "Test struct",
+			value: &literalOrder{
+				ID:        1,
+				Status:    "paid",
+				CreatedAt: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC),
+				Timeout:   time.Minute,
+				Items:     []literalItem{{SKU: "a", Quantity: Ptr(2)}, {SKU: "b", Price: 2}},
+				Refs:      []*literalItem{{SKU: "c"}, nil},
+				Tags:      map[string]int{"b": 2, "a": 1},
+				Extra:     map[string]any{"n": int64(1)},
+			},
+			expected: `&assertion.literalOrder{
+	ID:        1,
+	Status:    "paid",
+	CreatedAt: time.Date(2021, time.January, 1, 10, 0, 0, 0, time.UTC),
+	Timeout:   time.Minute,
+	Items: []assertion.literalItem{
+		{
+			SKU:      "a",
+			Quantity: assertion.Ptr(2),
+		},
+		{
+			SKU:   "b",
+			Price: 2.0,
+		},
+	},
+	Refs: []*assertion.literalItem{
+		{
+			SKU: "c",
+		},
+		nil,
+	},
+	Tags: map[string]int{
+		"a": 1,
+		"b": 2,
+	},
+	Extra: map[string]interface{}{
+		"n": int64(1),
+	},
+}`,
+		},
+		{
+			name:     "Test functions and channels",
+			value:    []any{func() {}, (chan int)(nil)},
+			expected: "[]interface{}{nil /* func() can't be written as a literal */, nil /* chan int */}",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := GoLiteral(tt.value)
+			if actual != tt.expected {
+				t.Errorf("GoLiteral returned:\n%s\nexpected:\n%s", actual, tt.expected)
+			}
+			if _, err := parser.ParseExpr(actual); err != nil {
+				t.Errorf("GoLiteral returned invalid Go: %v", err)
+			}
+		})
+	}
+}
+
+func TestGoLiteral_cycle(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	root := &node{Name: "a"}
+	root.Next = root
+	if actual := GoLiteral(root); !strings.Contains(actual, "Next: nil, /* cycle *assertion.node */") {
+		t.Errorf("GoLiteral didn't stop on the cycle:\n%s", actual)
+	}
+}
+
+func TestAssert_goLiteral(t *testing.T) {
+	actual := literalItem{SKU: "a", Quantity: Ptr(1)}
+
+	ok, message := Assert(actual, literalItem{SKU: "b"}, nil, WithGoLiteral())
+	if ok {
+		t.Fatal("Assert should fail")
+	}
+	expected := "Actual value as Go literal:\nassertion.literalItem{\n\tSKU:      \"a\",\n\tQuantity: assertion.Ptr(1),\n}"
+	if !strings.HasSuffix(message, expected) {
+		t.Errorf("message doesn't end with the Go literal:\n%s", message)
+	}
+
+	if ok, message := Assert(actual, actual, nil, WithGoLiteral()); !ok || message != "" {
+		t.Errorf("Assert added the Go literal to a match: %s", message)
+	}
+}
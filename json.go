@@ -0,0 +1,143 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// jsonNumberRegex matches the strings holding a number in the JSON syntax, e.g. "12.50" or "-1e3"
+var jsonNumberRegex = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// AssertJSON compares the actual and expected JSON documents using the custom assertions defined for the path or type
+// and returns the result and message, the same way as Assert.
+// paths follow the keys and indexes of the documents, e.g. $.items[0].price matched by the rule "$.items[].price",
+// and types are the ones decoded from JSON: string, json.Number, bool, map[string]interface {} and []interface {}.
+// numbers are kept as json.Number to avoid float drift and compared by value, so 1.0 and 1 are equal,
+// the time, number and string helpers accept string-encoded values, e.g. AssertTimeToDuration on RFC 3339 strings.
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		"$.created_at":    AssertTimeToDuration(time.Minute),
+//		"$.items[].price": AssertFloat64ToDecimalPlaces(2),
+//	}
+//	match, message := AssertJSON(body, []byte(`{"created_at": "2021-01-01T10:00:00Z", "items": [{"price": 1.5}]}`), customAssertions)
+func AssertJSON(actual []byte, expected []byte, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	actualDocument, err := decodeJSON(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual is not a valid JSON document: %v", err)
+	}
+	expectedDocument, err := decodeJSON(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected is not a valid JSON document: %v", err)
+	}
+	return Assert(actualDocument, expectedDocument, customAssertions, documentOptions(opts)...)
+}
+
+// AssertAsJSON compares the actual and expected values as the JSON documents they are serialized to by encoding/json
+// and returns the result and message, e.g. a typed response struct against a JSON fixture or a map[string]any.
+// fields are named by their json tags, omitempty and json:"-" are honored and MarshalJSON implementations are used,
+// so the comparison reflects what goes on the wire. string, []byte and json.RawMessage values are decoded as JSON documents,
+// e.g. a fixture written as a raw string literal, a JSON string value is written with its quotes, e.g. `"a"`.
+// paths and types are the ones of the documents, the same as AssertJSON, e.g. $.customer_id and json.Number
+// Example usage:
+//
+//	match, message := AssertAsJSON(response, `{"customer_id": 1, "items": [{"sku": "a"}]}`, nil)
+//	match, message := AssertAsJSON(response, map[string]any{"customer_id": 1, "items": []any{map[string]any{"sku": "a"}}}, nil)
+func AssertAsJSON(actual any, expected any, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	actualDocument, err := jsonDocument(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual can't be compared as JSON: %v", err)
+	}
+	expectedDocument, err := jsonDocument(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected can't be compared as JSON: %v", err)
+	}
+	return Assert(actualDocument, expectedDocument, customAssertions, documentOptions(opts)...)
+}
+
+// jsonDocument returns the JSON document of the value, string, []byte and json.RawMessage values are decoded as documents,
+// other values are serialized with encoding/json first
+func jsonDocument(value any) (any, error) {
+	switch typed := value.(type) {
+	case string:
+		return decodeJSON([]byte(typed))
+	case []byte:
+		return decodeJSON(typed)
+	case json.RawMessage:
+		return decodeJSON(typed)
+	}
+	return marshalJSONDocument(value)
+}
+
+// marshalJSONDocument serializes the value with encoding/json and decodes it back as a JSON document
+func marshalJSONDocument(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// documentValue returns the string-encoded value of a document converted to the type expected by the helper of this package
+// comparing it, e.g. "2021-01-01T10:00:00Z" to a time.Time for AssertTimeToDuration or "12.50" to a json.Number
+// for AssertFloat64WithTolerance, other values are returned as is
+func documentValue(customAssertion AssertionFunc, value reflect.Value) reflect.Value {
+	if value.Kind() != reflect.String || value.Type() == jsonNumberType {
+		return value
+	}
+	_, typ, ok := lookupRuleType(customAssertion)
+	switch {
+	case !ok:
+		return value
+	case typ == reflect.TypeOf(time.Time{}):
+		if parsed, err := time.Parse(time.RFC3339Nano, value.String()); err == nil {
+			return reflect.ValueOf(parsed)
+		}
+	case isNumberKind(typ.Kind()) && jsonNumberRegex.MatchString(value.String()):
+		return reflect.ValueOf(json.Number(value.String()))
+	}
+	return value
+}
+
+// isNumberKind checks if the kind is an integer or a float
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// decodeJSON decodes the JSON document keeping its numbers as json.Number
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON document")
+	}
+	return document, nil
+}
+
+// equalJSONNumbers checks if both values are json.Number with the same value, whatever their representation, e.g. 1.0, 1 and 1e0
+func equalJSONNumbers(actual reflect.Value, expected reflect.Value) bool {
+	if actual.Type() != jsonNumberType || expected.Type() != jsonNumberType {
+		return false
+	}
+	actualNumber, actualOk := new(big.Rat).SetString(actual.String())
+	expectedNumber, expectedOk := new(big.Rat).SetString(expected.String())
+	return actualOk && expectedOk && actualNumber.Cmp(expectedNumber) == 0
+}
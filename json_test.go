@@ -0,0 +1,324 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAssertJSON(t *testing.T) {
+	testTable := []struct {
+		name             string
+		actual           string
+		expected         string
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessage  string
+	}{
+		{
+			name:       "Test matching documents",
+			actual:     `{"id": 1, "tags": ["a", "b"], "paid": true, "note": null}`,
+			expected:   `{"note": null, "paid": true, "tags": ["a", "b"], "id": 1}`,
+			expectedOk: true,
+		},
+		{
+			name:       "Test numbers compared by value",
+			actual:     `{"price": 1.0, "count": 1e2, "big": 12345678901234567890}`,
+			expected:   `{"price": 1, "count": 100, "big": 12345678901234567890.0}`,
+			expectedOk: true,
+		},
+		{
+			name:            "Test numbers without float drift",
+			actual:          `{"big": 12345678901234567891}`,
+			expected:        `{"big": 12345678901234567890}`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.big\nExpected: json.Number(\"12345678901234567890\")\nActual:   json.Number(\"12345678901234567891\")\n(Should equal)!\n",
+		},
+		{
+			name:            "Test mismatches reported with JSON paths",
+			actual:          `{"items": [{"sku": "a", "qty": 1}, {"sku": "b", "qty": 2}]}`,
+			expected:        `{"items": [{"sku": "a", "qty": 1}, {"sku": "c", "qty": 2}]}`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.items[1].sku\nExpected: \"c\"\nActual:   \"b\"\n(Should equal)!\n",
+		},
+		{
+			name:            "Test values of different types",
+			actual:          `{"id": "1"}`,
+			expected:        `{"id": 1}`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.id\nExpected: json.Number(\"1\")\nActual:   \"1\"\n(Should equal)!",
+		},
+		{
+			name:            "Test missing key",
+			actual:          `{"id": 1, "name": "a"}`,
+			expected:        `{"id": 1, "title": "a"}`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.name\nKey name not found in expected\nPath: $.title\nKey title not found in actual",
+		},
+		{
+			name:            "Test extra key and other mismatches",
+			actual:          `{"a": {"x": 1, "y": 2, "z": 3}}`,
+			expected:        `{"a": {"x": 1, "y": 5}}`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.a.y\nExpected: json.Number(\"5\")\nActual:   json.Number(\"2\")\n(Should equal)!\nPath: $.a.z\nKey z not found in expected",
+		},
+		{
+			name:     "Test helpers on string-encoded values",
+			actual:   `{"created_at": "2021-01-01T10:00:30Z", "items": [{"price": "1.004"}, {"price": 2.001}], "total": "3"}`,
+			expected: `{"created_at": "2021-01-01T10:00:00Z", "items": [{"price": 1}, {"price": "2"}], "total": 3.4}`,
+			customAssertions: map[string]AssertionFunc{
+				"$.created_at":    AssertTimeToDuration(time.Minute),
+				"$.items[].price": AssertFloat64ToDecimalPlaces(2),
+				"$.total":         AssertNumberWithTolerance(0.5),
+			},
+			expectedOk: true,
+		},
+		{
+			name:     "Test integers beyond float64 precision",
+			actual:   `{"n": 9007199254740993}`,
+			expected: `{"n": 9007199254740992}`,
+			customAssertions: map[string]AssertionFunc{
+				"$.n": AssertNumberWithTolerance[int64](0),
+			},
+			expectedOk:      false,
+			expectedMessage: "Path: $.n\nExpected: 9007199254740992\nActual:   9007199254740993\n(Should equal)!\n",
+		},
+		{
+			name:     "Test type rule",
+			actual:   `{"a": 1.004, "b": [2.001]}`,
+			expected: `{"a": 1, "b": [2]}`,
+			customAssertions: map[string]AssertionFunc{
+				JSONNumberType: AssertFloat64WithTolerance(0.01),
+			},
+			expectedOk: true,
+		},
+		{
+			name:            "Test invalid actual",
+			actual:          `{"id": 1`,
+			expected:        `{"id": 1}`,
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid JSON document: unexpected EOF",
+		},
+		{
+			name:            "Test invalid expected",
+			actual:          `{"id": 1}`,
+			expected:        `{"id": 1} {"id": 2}`,
+			expectedOk:      false,
+			expectedMessage: "Expected is not a valid JSON document: unexpected data after the JSON document",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertJSON([]byte(tt.actual), []byte(tt.expected), tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertJSON returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertJSON returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+type jsonMoney int64
+
+func (m jsonMoney) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%02d"`, m/100, m%100)), nil
+}
+
+type jsonAudit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type jsonOrder struct {
+	jsonAudit
+	CustomerID int       `json:"customer_id"`
+	Total      jsonMoney `json:"total"`
+	Coupon     string    `json:"coupon,omitempty"`
+	Secret     string    `json:"-"`
+	Items      []jsonItem
+}
+
+type jsonItem struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
+
+func TestAssertAsJSON(t *testing.T) {
+	order := jsonOrder{
+		jsonAudit:  jsonAudit{CreatedBy: "a"},
+		CustomerID: 1,
+		Total:      1250,
+		Secret:     "secret",
+		Items:      []jsonItem{{SKU: "a", Price: 1.5}},
+	}
+
+	testTable := []struct {
+		name             string
+		actual           any
+		expected         any
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessage  string
+	}{
+		{
+			name:       "Test struct against JSON fixture",
+			actual:     order,
+			expected:   []byte(`{"created_by": "a", "customer_id": 1, "total": "12.50", "Items": [{"sku": "a", "price": 1.50}]}`),
+			expectedOk: true,
+		},
+		{
+			name:   "Test struct against map",
+			actual: &order,
+			expected: map[string]any{
+				"created_by":  "a",
+				"customer_id": 1,
+				"total":       "12.50",
+				"Items":       []any{map[string]any{"sku": "a", "price": 1.5}},
+			},
+			expectedOk: true,
+		},
+		{
+			name:       "Test map against struct",
+			actual:     map[string]any{"sku": "a", "price": 1.5},
+			expected:   jsonItem{SKU: "a", Price: 1.5},
+			expectedOk: true,
+		},
+		{
+			name:       "Test struct against string fixture",
+			actual:     order,
+			expected:   `{"created_by": "a", "customer_id": 1, "total": "12.50", "Items": [{"sku": "a", "price": 1.5}]}`,
+			expectedOk: true,
+		},
+		{
+			name:            "Test invalid string fixture",
+			actual:          order,
+			expected:        `{"created_by": "a"`,
+			expectedOk:      false,
+			expectedMessage: "Expected can't be compared as JSON: unexpected EOF",
+		},
+		{
+			name:            "Test omitted field",
+			actual:          order,
+			expected:        json.RawMessage(`{"created_by": "a", "customer_id": 1, "total": "12.50", "coupon": "", "Items": [{"sku": "a", "price": 1.5}]}`),
+			expectedOk:      false,
+			expectedMessage: "Path: $.coupon\nKey coupon not found in actual",
+		},
+		{
+			name:            "Test mismatch reported with JSON path",
+			actual:          order,
+			expected:        []byte(`{"created_by": "a", "customer_id": 1, "total": "12.05", "Items": [{"sku": "a", "price": 1.5}]}`),
+			expectedOk:      false,
+			expectedMessage: "Path: $.total\nExpected: \"12.05\"\nActual:   \"12.50\"\n(Should equal)!\n",
+		},
+		{
+			name:     "Test custom assertions on JSON paths",
+			actual:   order,
+			expected: []byte(`{"created_by": "b", "customer_id": 1, "total": 12.5, "Items": [{"sku": "a", "price": 1.499}]}`),
+			customAssertions: map[string]AssertionFunc{
+				"$.created_by":    SkipAssertion,
+				"$.total":         AssertFloat64WithTolerance(0.001),
+				"$.Items[].price": AssertFloat64ToDecimalPlaces(2),
+			},
+			expectedOk: true,
+		},
+		{
+			name:            "Test value not serializable",
+			actual:          func() {},
+			expected:        []byte(`{}`),
+			expectedOk:      false,
+			expectedMessage: "Actual can't be compared as JSON: json: unsupported type: func()",
+		},
+		{
+			name:            "Test invalid fixture",
+			actual:          order,
+			expected:        []byte(`{`),
+			expectedOk:      false,
+			expectedMessage: "Expected can't be compared as JSON: unexpected EOF",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertAsJSON(tt.actual, tt.expected, tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertAsJSON returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertAsJSON returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestDocumentValue(t *testing.T) {
+	testTime := time.Date(2021, time.January, 1, 10, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name            string
+		customAssertion AssertionFunc
+		value           any
+		expectedValue   any
+	}{
+		{
+			name:            "Test RFC 3339 string for a time helper",
+			customAssertion: AssertTimeToDuration(time.Second),
+			value:           "2021-01-01T10:00:00Z",
+			expectedValue:   testTime,
+		},
+		{
+			name:            "Test other string for a time helper",
+			customAssertion: AssertTimeToDuration(time.Second),
+			value:           "2021-01-01",
+			expectedValue:   "2021-01-01",
+		},
+		{
+			name:            "Test numeric string for a number helper",
+			customAssertion: AssertFloat64WithTolerance(0.1),
+			value:           "-12.50",
+			expectedValue:   json.Number("-12.50"),
+		},
+		{
+			name:            "Test other string for a number helper",
+			customAssertion: AssertNumberWithTolerance[int64](1),
+			value:           "12,50",
+			expectedValue:   "12,50",
+		},
+		{
+			name:            "Test RFC 3339 string for a string helper",
+			customAssertion: AssertStringWithDistance(1),
+			value:           "2021-01-01T10:00:00Z",
+			expectedValue:   "2021-01-01T10:00:00Z",
+		},
+		{
+			name:            "Test RFC 3339 string for a custom function",
+			customAssertion: SkipAssertion,
+			value:           "2021-01-01T10:00:00Z",
+			expectedValue:   "2021-01-01T10:00:00Z",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if value := documentValue(tt.customAssertion, reflect.ValueOf(tt.value)).Interface(); value != tt.expectedValue {
+				t.Errorf("documentValue returned %#v, expected %#v", value, tt.expectedValue)
+			}
+		})
+	}
+
+	// values of Go strings are compared as is
+	customAssertions := map[string]AssertionFunc{"$.At": AssertTimeToDuration(time.Minute)}
+	ok, _ := Assert(struct{ At string }{At: "2021-01-01T10:00:30Z"}, struct{ At string }{At: "2021-01-01T10:00:00Z"}, customAssertions)
+	if ok {
+		t.Error("Assert should compare Go strings as strings")
+	}
+	ok, message := AssertJSON([]byte(`{"At": "2021-01-01T10:00:30Z"}`), []byte(`{"At": "2021-01-01T10:00:00Z"}`), customAssertions)
+	if !ok {
+		t.Errorf("AssertJSON should parse the timestamps: %s", message)
+	}
+}
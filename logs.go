@@ -0,0 +1,269 @@
+package assertion
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// logTimeRule is the rule of the times of the log entries, they are skipped unless a custom assertion is defined for it
+const logTimeRule = "$[].time"
+
+// LogEntry is a log record captured by LogRecorder, keyed by "time", "level", "message" and "attrs",
+// e.g. {"level": "INFO", "message": "order paid", "attrs": {"order_id": 1, "http": {"status": 200}}}
+// attrs are nested in maps by group and left out when the record has none, the time is left out when it is zero
+type LogEntry = map[string]any
+
+// LogMatching is how AssertLogs matches the captured entries with the expected entries
+type LogMatching int
+
+const (
+	// LogsInOrder expects exactly the expected entries in the same order
+	LogsInOrder LogMatching = iota
+	// LogsInAnyOrder expects exactly the expected entries in any order
+	LogsInAnyOrder
+	// LogsContaining expects the expected entries in the same order, other entries may come before, between and after them,
+	// matched the same way as ContainsInOrder with the expected entries as steps
+	LogsContaining
+)
+
+// LogRecorder is a slog.Handler capturing the records of every level as LogEntry values, e.g. slog.New(recorder),
+// handlers returned by WithAttrs and WithGroup record into the same recorder.
+type LogRecorder struct {
+	state *logState
+	// attrs are the attributes added by WithAttrs, in the groups opened before them
+	attrs []groupedAttr
+	// groups are the groups opened by WithGroup
+	groups []string
+}
+
+// logState holds the entries captured by a LogRecorder and the handlers derived from it
+type logState struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// groupedAttr is an attribute with the groups it is nested in
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewLogRecorder returns an empty LogRecorder
+func NewLogRecorder() *LogRecorder {
+	return &LogRecorder{state: &logState{}}
+}
+
+// Enabled records every level
+func (r *LogRecorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle captures the record
+func (r *LogRecorder) Handle(_ context.Context, record slog.Record) error {
+	attrs := map[string]any{}
+	for _, grouped := range r.attrs {
+		addLogAttr(attrs, grouped.groups, grouped.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addLogAttr(attrs, r.groups, attr)
+		return true
+	})
+
+	entry := LogEntry{"level": record.Level.String(), "message": record.Message}
+	if !record.Time.IsZero() {
+		entry["time"] = record.Time
+	}
+	if len(attrs) > 0 {
+		entry["attrs"] = attrs
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.entries = append(r.state.entries, entry)
+	return nil
+}
+
+// WithAttrs returns a handler adding the attributes to the records, in the groups opened so far
+func (r *LogRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *r
+	derived.attrs = slices.Clip(derived.attrs)
+	for _, attr := range attrs {
+		derived.attrs = append(derived.attrs, groupedAttr{groups: r.groups, attr: attr})
+	}
+	return &derived
+}
+
+// WithGroup returns a handler nesting the attributes added afterwards in the group
+func (r *LogRecorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	derived := *r
+	derived.groups = append(slices.Clip(derived.groups), name)
+	return &derived
+}
+
+// Entries returns the entries captured so far
+func (r *LogRecorder) Entries() []LogEntry {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return slices.Clone(r.state.entries)
+}
+
+// Reset removes the entries captured so far
+func (r *LogRecorder) Reset() {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.entries = nil
+}
+
+// addLogAttr adds the attribute to the attributes in the groups, the same way as the handlers of slog:
+// attributes with an empty key and empty groups are ignored, groups with an empty key are inlined
+func addLogAttr(attrs map[string]any, groups []string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Key == "" && value.Kind() != slog.KindGroup || value.Kind() == slog.KindGroup && len(value.Group()) == 0 {
+		return
+	}
+	for _, group := range groups {
+		nested, ok := attrs[group].(map[string]any)
+		if !ok {
+			nested = map[string]any{}
+			attrs[group] = nested
+		}
+		attrs = nested
+	}
+	if value.Kind() != slog.KindGroup {
+		attrs[attr.Key] = logValue(value)
+		return
+	}
+	if attr.Key != "" {
+		groups = []string{attr.Key}
+	} else {
+		groups = nil
+	}
+	for _, member := range value.Group() {
+		addLogAttr(attrs, groups, member)
+	}
+}
+
+// logValue returns the value of a resolved attribute, errors are their message
+func logValue(value slog.Value) any {
+	if err, ok := value.Any().(error); ok && value.Kind() == slog.KindAny {
+		return err.Error()
+	}
+	return value.Any()
+}
+
+// AssertLogs compares the entries captured by the recorder with the expected entries using the custom assertions defined
+// for the paths or types of the entries, e.g. "$[].attrs.request_id": SkipAssertion, and returns the result and message.
+// the entries are compared as the JSON documents they are serialized to, the same as AssertAsJSON,
+// times are skipped unless a custom assertion is defined for "$[].time", so expected entries leave them out.
+// matching is LogsInOrder, LogsInAnyOrder or LogsContaining, see LogMatching
+// Example usage:
+//
+//	recorder := assertion.NewLogRecorder()
+//	service := NewService(slog.New(recorder))
+//	service.Pay(ctx, order)
+//	match, message := assertion.AssertLogs(recorder, []assertion.LogEntry{
+//		{"level": "INFO", "message": "order paid", "attrs": map[string]any{"order_id": 1, "request_id": ""}},
+//	}, assertion.LogsContaining, map[string]assertion.AssertionFunc{
+//		"$[].attrs.request_id": assertion.SkipAssertion,
+//	})
+func AssertLogs(recorder *LogRecorder, expected []LogEntry, matching LogMatching, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	actualDocuments, err := logDocuments(recorder.Entries(), customAssertions)
+	if err != nil {
+		return false, fmt.Sprintf("Captured logs can't be compared as JSON: %v", err)
+	}
+	expectedDocuments, err := logDocuments(expected, customAssertions)
+	if err != nil {
+		return false, fmt.Sprintf("Expected logs can't be compared as JSON: %v", err)
+	}
+	opts = documentOptions(opts)
+
+	switch matching {
+	case LogsInAnyOrder:
+		return assertLogsInAnyOrder(actualDocuments, expectedDocuments, customAssertions, opts...)
+	case LogsContaining:
+		nouns := sequenceNouns{step: "expected entry", element: "captured entry", sequence: "the captured logs"}
+		if message := containsInOrder(actualDocuments, expectedDocuments, nouns, customAssertions, opts...); message != "" {
+			return false, message
+		}
+		return true, ""
+	}
+	if len(actualDocuments) != len(expectedDocuments) {
+		return false, fmt.Sprintf("Expected %d log entries, captured %d\nExpected:\n%s\nCaptured:\n%s",
+			len(expectedDocuments), len(actualDocuments), formatLogEntries(expectedDocuments), formatLogEntries(actualDocuments))
+	}
+	return Assert(actualDocuments, expectedDocuments, customAssertions, opts...)
+}
+
+// logDocuments returns the JSON documents of the entries, without their times unless a custom assertion is defined for them
+func logDocuments(entries []LogEntry, customAssertions map[string]AssertionFunc) ([]any, error) {
+	documents := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		document, err := marshalJSONDocument(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := customAssertions[logTimeRule]; !ok {
+			if fields, ok := document.(map[string]any); ok {
+				delete(fields, "time")
+			}
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// assertLogsInAnyOrder matches every expected entry with the first captured entry matching it not matched yet
+func assertLogsInAnyOrder(actual []any, expected []any, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	matched := make([]bool, len(actual))
+	messages := []string{}
+	for i, entry := range expected {
+		found := false
+		for j := range actual {
+			if !matched[j] {
+				if ok, _, _ := compareElement(actual[j], entry, j, customAssertions, opts...); ok {
+					matched[j], found = true, true
+					break
+				}
+			}
+		}
+		if !found {
+			candidates := []int{}
+			for j := range actual {
+				if !matched[j] {
+					candidates = append(candidates, j)
+				}
+			}
+			messages = append(messages, fmt.Sprintf("Expected entry %d not found in the captured logs%s", i, closestElement("captured entry", actual, candidates, entry, customAssertions, opts...)))
+		}
+	}
+	for j, entry := range actual {
+		if !matched[j] {
+			messages = append(messages, fmt.Sprintf("Captured entry %d not expected: %s", j, formatLogEntry(entry)))
+		}
+	}
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "\n")
+	}
+	return true, ""
+}
+
+// formatLogEntries lists the level and message of the entries
+func formatLogEntries(entries []any) string {
+	lines := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%d: %s", i, formatLogEntry(entry)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatLogEntry returns the level and message of the entry, e.g. INFO "order paid"
+func formatLogEntry(entry any) string {
+	fields, _ := entry.(map[string]any)
+	return fmt.Sprintf("%v %q", fields["level"], fmt.Sprint(fields["message"]))
+}
@@ -0,0 +1,171 @@
+package assertion
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLogRecorder(t *testing.T) {
+	recorder := NewLogRecorder()
+	logger := slog.New(recorder)
+	logger.With("request_id", "r1").WithGroup("http").Info("request", "method", "GET", slog.Group("", "inline", 1), slog.Group("empty"))
+	logger.Debug("slow", "took", 2*time.Second, "err", errors.New("timeout"))
+	logger.Error("failed", slog.Group("order", "id", 7, slog.Group("customer", "id", "c1")))
+
+	expected := []LogEntry{
+		{
+			"level":   "INFO",
+			"message": "request",
+			"attrs":   map[string]any{"request_id": "r1", "http": map[string]any{"method": "GET", "inline": int64(1)}},
+		},
+		{
+			"level":   "DEBUG",
+			"message": "slow",
+			"attrs":   map[string]any{"took": 2 * time.Second, "err": "timeout"},
+		},
+		{
+			"level":   "ERROR",
+			"message": "failed",
+			"attrs":   map[string]any{"order": map[string]any{"id": int64(7), "customer": map[string]any{"id": "c1"}}},
+		},
+	}
+	entries := recorder.Entries()
+	for _, entry := range entries {
+		if _, ok := entry["time"].(time.Time); !ok {
+			t.Errorf("entry %v should have a time", entry)
+		}
+		delete(entry, "time")
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("LogRecorder captured %v, expected %v", entries, expected)
+	}
+
+	recorder.Reset()
+	if entries := recorder.Entries(); len(entries) != 0 {
+		t.Errorf("LogRecorder captured %v after Reset", entries)
+	}
+}
+
+func TestAssertLogs(t *testing.T) {
+	recorder := NewLogRecorder()
+	logger := slog.New(recorder).With("request_id", "r1")
+	logger.Info("order created", "order_id", 7)
+	logger.Debug("cache miss", "key", "order:7")
+	logger.Info("order paid", "order_id", 7, "amount", 10.5)
+	logger.Warn("shipping delayed", "order_id", 7)
+
+	requestID := map[string]AssertionFunc{"$[].attrs.request_id": SkipAssertion}
+	created := LogEntry{"level": "INFO", "message": "order created", "attrs": map[string]any{"order_id": 7, "request_id": ""}}
+	miss := LogEntry{"level": "DEBUG", "message": "cache miss", "attrs": map[string]any{"key": "order:7", "request_id": ""}}
+	paid := LogEntry{"level": "INFO", "message": "order paid", "attrs": map[string]any{"order_id": 7, "amount": 10.5, "request_id": ""}}
+	delayed := LogEntry{"level": "WARN", "message": "shipping delayed", "attrs": map[string]any{"order_id": 7, "request_id": ""}}
+
+	testTable := []struct {
+		name             string
+		expected         []LogEntry
+		matching         LogMatching
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessage  string
+	}{
+		{
+			name:             "Test in order",
+			expected:         []LogEntry{created, miss, paid, delayed},
+			matching:         LogsInOrder,
+			customAssertions: requestID,
+			expectedOk:       true,
+		},
+		{
+			name:             "Test in order with a different entry",
+			expected:         []LogEntry{created, miss, delayed, paid},
+			matching:         LogsInOrder,
+			customAssertions: map[string]AssertionFunc{"$[].attrs": SkipAssertion},
+			expectedOk:       false,
+			expectedMessage: "Path: $[2].level\nExpected: \"WARN\"\nActual:   \"INFO\"\n(Should equal)!\n" +
+				"Path: $[2].message\nExpected: \"shipping delayed\"\nActual:   \"order paid\"\n(Should equal)!\n" +
+				"Path: $[3].level\nExpected: \"INFO\"\nActual:   \"WARN\"\n(Should equal)!\n" +
+				"Path: $[3].message\nExpected: \"order paid\"\nActual:   \"shipping delayed\"\n(Should equal)!",
+		},
+		{
+			name:             "Test in order with missing entries",
+			expected:         []LogEntry{created, paid},
+			matching:         LogsInOrder,
+			customAssertions: requestID,
+			expectedOk:       false,
+			expectedMessage: "Expected 2 log entries, captured 4\n" +
+				"Expected:\n0: INFO \"order created\"\n1: INFO \"order paid\"\n" +
+				"Captured:\n0: INFO \"order created\"\n1: DEBUG \"cache miss\"\n2: INFO \"order paid\"\n3: WARN \"shipping delayed\"",
+		},
+		{
+			name:             "Test in any order",
+			expected:         []LogEntry{delayed, paid, miss, created},
+			matching:         LogsInAnyOrder,
+			customAssertions: requestID,
+			expectedOk:       true,
+		},
+		{
+			name:             "Test in any order with a different entry",
+			expected:         []LogEntry{delayed, miss, created, {"level": "INFO", "message": "order paid", "attrs": map[string]any{"order_id": 7, "amount": 12, "request_id": ""}}},
+			matching:         LogsInAnyOrder,
+			customAssertions: requestID,
+			expectedOk:       false,
+			expectedMessage: "Expected entry 3 not found in the captured logs, the closest captured entry 2 doesn't match:\n" +
+				"Path: $[2].attrs.amount\nExpected: json.Number(\"12\")\nActual:   json.Number(\"10.5\")\n(Should equal)!\n" +
+				"Captured entry 2 not expected: INFO \"order paid\"",
+		},
+		{
+			name:             "Test containing subsequence",
+			expected:         []LogEntry{created, delayed},
+			matching:         LogsContaining,
+			customAssertions: requestID,
+			expectedOk:       true,
+		},
+		{
+			name:             "Test containing entries out of order",
+			expected:         []LogEntry{paid, created},
+			matching:         LogsContaining,
+			customAssertions: requestID,
+			expectedOk:       false,
+			expectedMessage: "Expected entry 1 not found after captured entry 2 matching expected entry 0, the closest captured entry 3 doesn't match:\n" +
+				"Path: $[3].level\nExpected: \"INFO\"\nActual:   \"WARN\"\n(Should equal)!\n" +
+				"Path: $[3].message\nExpected: \"order created\"\nActual:   \"shipping delayed\"\n(Should equal)!",
+		},
+		{
+			name:       "Test containing without the skipped attribute",
+			expected:   []LogEntry{{"level": "INFO", "message": "order created", "attrs": map[string]any{"order_id": 7}}},
+			matching:   LogsContaining,
+			expectedOk: false,
+			expectedMessage: "Expected entry 0 not found in the captured logs, the closest captured entry 0 doesn't match:\n" +
+				"Path: $[0].attrs.request_id\nKey request_id not found in expected",
+		},
+		{
+			name:     "Test times compared with a rule",
+			expected: []LogEntry{{"time": time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), "level": "INFO", "message": "order created", "attrs": map[string]any{"order_id": 7, "request_id": "r1"}}},
+			matching: LogsContaining,
+			customAssertions: map[string]AssertionFunc{"$[].time": func(actual any, expected ...any) string {
+				if _, err := time.Parse(time.RFC3339Nano, actual.(string)); err != nil {
+					return "not a time"
+				}
+				return ""
+			}},
+			expectedOk: true,
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertLogs(recorder, tt.expected, tt.matching, tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertLogs returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertLogs returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+const (
+	UUIDPlaceholder      = "<uuid>"
+	TimestampPlaceholder = "<timestamp>"
+)
+
+var uuidRegex = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+var timestampRegex = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[Tt ]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})`)
+
+// Normalizer returns the value replacing the given value before comparing or snapshotting it, see WithNormalizers
+type Normalizer func(value any) any
+
+// Placeholder is a normalizer replacing any value but nil by the placeholder, e.g. Placeholder("<token>") to hide secrets
+func Placeholder(placeholder string) Normalizer {
+	return func(value any) any {
+		if value == nil {
+			return nil
+		}
+		return placeholder
+	}
+}
+
+// ReplacePattern is a normalizer replacing the matches of the pattern in strings by the placeholder, other values are kept
+func ReplacePattern(pattern *regexp.Regexp, placeholder string) Normalizer {
+	return func(value any) any {
+		if str, ok := value.(string); ok {
+			return pattern.ReplaceAllLiteralString(str, placeholder)
+		}
+		return value
+	}
+}
+
+// NormalizeUUIDs is a normalizer replacing the UUIDs found in strings by <uuid>, e.g. "/orders/<uuid>"
+func NormalizeUUIDs() Normalizer {
+	return ReplacePattern(uuidRegex, UUIDPlaceholder)
+}
+
+// NormalizeTimestamps is a normalizer replacing time.Time values and the RFC 3339 timestamps found in strings by <timestamp>
+func NormalizeTimestamps() Normalizer {
+	replace := ReplacePattern(timestampRegex, TimestampPlaceholder)
+	return func(value any) any {
+		if _, ok := value.(time.Time); ok {
+			return TimestampPlaceholder
+		}
+		return replace(value)
+	}
+}
+
+// normalizer returns the normalizer defined for the path, one of its aliases, or type
+// the path takes precedence over its aliases, and both over the type
+func (w *walker) normalizer(path string, typ reflect.Type) (Normalizer, bool) {
+	if len(w.normalizers) == 0 {
+		return nil, false
+	}
+	for _, candidate := range append([]string{path}, w.aliasesOf(path)...) {
+		if key, ok := customAssertionKey(candidate, nil, w.normalizers); ok {
+			return w.normalizers[key], true
+		}
+	}
+	if key, ok := customAssertionKey(path, typ, w.normalizers); ok {
+		return w.normalizers[key], true
+	}
+	return nil, false
+}
+
+// normalizeValue returns the value replaced by the normalizer, a nil result is returned as an invalid value
+func normalizeValue(normalizer Normalizer, value reflect.Value) reflect.Value {
+	return reflect.ValueOf(normalizer(getValue(value)))
+}
+
+// normalizeTree replaces the values of the decoded JSON document matching the normalizers, see WithNormalizers
+// types are the Go types of the values by path, see documentTypes, used before the types of the document to look up normalizers.
+// the document is modified in place and returned
+func normalizeTree(value any, normalizers map[string]Normalizer, types map[string]reflect.Type, path string) any {
+	key, ok := customAssertionKey(path, types[path], normalizers)
+	if !ok {
+		key, ok = customAssertionKey(path, reflect.TypeOf(value), normalizers)
+	}
+	if ok {
+		return normalizers[key](value)
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, item := range typed {
+			typed[key] = normalizeTree(item, normalizers, types, path+"."+key)
+		}
+	case []any:
+		for i, item := range typed {
+			typed[i] = normalizeTree(item, normalizers, types, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+	return value
+}
@@ -0,0 +1,168 @@
+package assertion
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizers(t *testing.T) {
+	testTime := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name       string
+		normalizer Normalizer
+		value      any
+		expected   any
+	}{
+		{
+			name:       "Test placeholder",
+			normalizer: Placeholder("<token>"),
+			value:      "secret",
+			expected:   "<token>",
+		},
+		{
+			name:       "Test placeholder of number",
+			normalizer: Placeholder("<id>"),
+			value:      42,
+			expected:   "<id>",
+		},
+		{
+			name:       "Test placeholder keeps nil",
+			normalizer: Placeholder("<token>"),
+			value:      nil,
+			expected:   nil,
+		},
+		{
+			name:       "Test pattern",
+			normalizer: ReplacePattern(regexp.MustCompile(`\d+`), "<n>"),
+			value:      "order 12 of 30",
+			expected:   "order <n> of <n>",
+		},
+		{
+			name:       "Test pattern keeps other types",
+			normalizer: ReplacePattern(regexp.MustCompile(`\d+`), "<n>"),
+			value:      12,
+			expected:   12,
+		},
+		{
+			name:       "Test UUIDs",
+			normalizer: NormalizeUUIDs(),
+			value:      "/orders/0B6F3C2E-8A4D-4F1E-9C3B-2D5E7F9A1B3C/items",
+			expected:   "/orders/<uuid>/items",
+		},
+		{
+			name:       "Test timestamps in string",
+			normalizer: NormalizeTimestamps(),
+			value:      "created at 2021-01-01T10:00:00.123+02:00, updated at 2021-01-02T10:00:00Z",
+			expected:   "created at <timestamp>, updated at <timestamp>",
+		},
+		{
+			name:       "Test time value",
+			normalizer: NormalizeTimestamps(),
+			value:      testTime,
+			expected:   "<timestamp>",
+		},
+		{
+			name:       "Test date without time",
+			normalizer: NormalizeTimestamps(),
+			value:      "2021-01-01",
+			expected:   "2021-01-01",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.normalizer(tt.value); actual != tt.expected {
+				t.Errorf("normalizer returned %#v, expected %#v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAssert_normalizers(t *testing.T) {
+	type event struct {
+		ID        string
+		CreatedAt time.Time
+		Payload   map[string]any
+		Note      any
+	}
+	actual := event{
+		ID:        "0b6f3c2e-8a4d-4f1e-9c3b-2d5e7f9a1b3c",
+		CreatedAt: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC),
+		Payload:   map[string]any{"token": "a", "count": 1},
+		Note:      "a",
+	}
+	expected := event{
+		ID:        "7d2a9e4b-1c3f-4a5d-8e6b-9f0a1b2c3d4e",
+		CreatedAt: time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC),
+		Payload:   map[string]any{"token": "b", "count": 1},
+		Note:      "b",
+	}
+
+	testTable := []struct {
+		name             string
+		normalizers      map[string]Normalizer
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessages []string
+	}{
+		{
+			name:       "Test without normalizers",
+			expectedOk: false,
+			expectedMessages: []string{
+				"Path: $.ID",
+				"Path: $.CreatedAt",
+				"Path: $.Payload.token",
+				"Path: $.Note",
+			},
+		},
+		{
+			name: "Test normalized paths and types",
+			normalizers: map[string]Normalizer{
+				"$.ID":            NormalizeUUIDs(),
+				TimeType:          NormalizeTimestamps(),
+				"$.Payload.token": Placeholder("<token>"),
+				"interface {}":    Placeholder("<any>"),
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test values inside interfaces are walked",
+			normalizers: map[string]Normalizer{
+				"$.ID":   NormalizeUUIDs(),
+				TimeType: NormalizeTimestamps(),
+				"string": Placeholder("<string>"),
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test custom assertions compare the normalized values",
+			normalizers: map[string]Normalizer{
+				"$.ID": Placeholder("<id>"),
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.ID":         AssertStringWithCleanup(strings.ToUpper),
+				TimeType:       SkipAssertion,
+				"$.Payload":    SkipAssertion,
+				"interface {}": SkipAssertion,
+			},
+			expectedOk: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := Assert(actual, expected, tt.customAssertions, WithNormalizers(tt.normalizers))
+			if ok != tt.expectedOk {
+				t.Fatalf("Assert returned %v: %s", ok, message)
+			}
+			for _, expectedMessage := range tt.expectedMessages {
+				if !strings.Contains(message, expectedMessage) {
+					t.Errorf("message doesn't contain %q:\n%s", expectedMessage, message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package assertion
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Option configures how Assert walks and reports the compared values.
+type Option func(*config)
+
+// config holds the settings applied by the options passed to Assert
+type config struct {
+	unusedRules unusedRulesMode
+	naming      NamingStrategy
+	promote     bool
+	maxFailures int
+	unifiedDiff bool
+	// color enables colors in the unified diff, nil detects it from the environment, see colorEnabled
+	color     *bool
+	goLiteral bool
+	// normalizers replace the values of a path or type on both sides before comparing them, see WithNormalizers
+	normalizers map[string]Normalizer
+	// documents is set when the values are decoded documents, e.g. JSON, the helpers then accept string-encoded values,
+	// see documentValue
+	documents bool
+}
+
+// documentOptions returns the options comparing the values as decoded documents, see config.documents
+func documentOptions(opts []Option) []Option {
+	return append(slices.Clip(opts), func(cfg *config) {
+		cfg.documents = true
+	})
+}
+
+// unusedRulesMode defines what happens to custom assertions that matched no path or type
+type unusedRulesMode int
+
+const (
+	ignoreUnusedRules unusedRulesMode = iota
+	warnUnusedRules
+	failUnusedRules
+)
+
+// newConfig returns the config built from the default settings and the given options
+func newConfig(opts ...Option) config {
+	cfg := config{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// WithStrictRules makes Assert fail when a custom assertion matched no path or type,
+// listing every unused rule with a "did you mean" suggestion based on the visited paths.
+// it catches typos in rule keys which would otherwise make the assertion pass for the wrong reason.
+// rules below nil values and empty slices or maps are checked on their static types, e.g. "$.Items[].Price" with no items.
+func WithStrictRules() Option {
+	return func(cfg *config) {
+		cfg.unusedRules = failUnusedRules
+	}
+}
+
+// WithUnusedRulesWarning reports the custom assertions that matched no path or type
+// at the end of the message without failing the assertion.
+func WithUnusedRulesWarning() Option {
+	return func(cfg *config) {
+		cfg.unusedRules = warnUnusedRules
+	}
+}
+
+// NamingStrategy returns the name of the struct field used in paths,
+// both to render the paths in the messages and to match the custom assertion keys
+type NamingStrategy func(field reflect.StructField) string
+
+// GoFieldNames names the fields of the paths by their Go names, e.g. $.CustomerID, it is the default
+func GoFieldNames(field reflect.StructField) string {
+	return field.Name
+}
+
+// JSONTagNames names the fields of the paths by their json tags, e.g. $.customer_id
+// fields without a json tag name keep their Go name
+func JSONTagNames(field reflect.StructField) string {
+	return tagName(field, "json")
+}
+
+// TagNames returns a NamingStrategy naming the fields of the paths by the given struct tag, e.g. TagNames("yaml")
+// fields without a name in the tag keep their Go name
+func TagNames(tag string) NamingStrategy {
+	return func(field reflect.StructField) string {
+		return tagName(field, tag)
+	}
+}
+
+// tagName returns the name in the struct tag of the field, ignoring tag options such as omitempty
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// WithNaming sets the NamingStrategy used for the field names of the paths
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		"$.customer_id": SkipAssertion,
+//	}
+//	match, message := Assert(actual, expected, customAssertions, WithNaming(JSONTagNames))
+func WithNaming(strategy NamingStrategy) Option {
+	return func(cfg *config) {
+		cfg.naming = strategy
+	}
+}
+
+// fieldName returns the name of the field in paths using the configured NamingStrategy
+func (cfg config) fieldName(field reflect.StructField) string {
+	if cfg.naming == nil {
+		return field.Name
+	}
+	return cfg.naming(field)
+}
+
+// WithPromotedFields addresses the fields of embedded structs by their promoted path, e.g. $.CreatedAt instead of $.BaseModel.CreatedAt,
+// the same way they are accessed in code. Messages use the promoted path, rule keys accept both the promoted and the embedded path.
+// Fields follow the Go promotion rules: a field shadowed by a shallower field with the same name,
+// or sharing its name with another field at the same depth, is not promoted and keeps its embedded path.
+func WithPromotedFields() Option {
+	return func(cfg *config) {
+		cfg.promote = true
+	}
+}
+
+// WithMaxFailures stops comparing once the number of failures is reached, useful for huge comparisons
+// by default every failure is collected, a non positive max collects every failure too
+func WithMaxFailures(max int) Option {
+	return func(cfg *config) {
+		cfg.maxFailures = max
+	}
+}
+
+// WithFailFast stops comparing at the first failure, it is the same as WithMaxFailures(1)
+func WithFailFast() Option {
+	return WithMaxFailures(1)
+}
+
+// WithUnifiedDiff adds to the message a unified diff of the whole structure,
+// with the expected values of the failing paths marked by - and the actual values by +.
+// unchanged subtrees are elided, colors are enabled on terminals unless NO_COLOR or CI is set, see WithColor
+func WithUnifiedDiff() Option {
+	return func(cfg *config) {
+		cfg.unifiedDiff = true
+	}
+}
+
+// WithColor enables or disables the colors of the unified diff regardless of the environment
+func WithColor(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.color = &enabled
+	}
+}
+
+// WithGoLiteral adds to the message of a failing assertion the Go literal of the actual value, see GoLiteral,
+// to paste it in the test as the expected value once the actual value is checked to be right
+func WithGoLiteral() Option {
+	return func(cfg *config) {
+		cfg.goLiteral = true
+	}
+}
+
+// WithNormalizers replaces the values matching the path or type keys on both sides before comparing them,
+// keyed the same way as the custom assertions, e.g. "$.ID": Placeholder("<id>") or TimeType: NormalizeTimestamps().
+// MatchSnapshot also normalizes the value before writing it, so snapshots stay stable and secrets are never written.
+// normalizers are applied before the custom assertions, using the option again adds the normalizers to the previous ones
+func WithNormalizers(normalizers map[string]Normalizer) Option {
+	return func(cfg *config) {
+		if cfg.normalizers == nil {
+			cfg.normalizers = map[string]Normalizer{}
+		}
+		for key, normalizer := range normalizers {
+			cfg.normalizers[key] = normalizer
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package assertion
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	type testStruct struct {
+		CustomerID string `json:"customer_id,omitempty" yaml:"customerId"`
+		Skipped    string `json:"-"`
+		Options    string `json:",omitempty"`
+		Plain      string
+	}
+	typ := reflect.TypeOf(testStruct{})
+
+	testTable := []struct {
+		name          string
+		strategy      NamingStrategy
+		expectedNames []string
+	}{
+		{
+			name:          "Test with Go field names",
+			strategy:      GoFieldNames,
+			expectedNames: []string{"CustomerID", "Skipped", "Options", "Plain"},
+		},
+		{
+			name:          "Test with json tag names",
+			strategy:      JSONTagNames,
+			expectedNames: []string{"customer_id", "Skipped", "Options", "Plain"},
+		},
+		{
+			name:          "Test with yaml tag names",
+			strategy:      TagNames("yaml"),
+			expectedNames: []string{"customerId", "Skipped", "Options", "Plain"},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, expectedName := range tt.expectedNames {
+				if name := tt.strategy(typ.Field(i)); name != expectedName {
+					t.Errorf("Expected name: %s, got: %s", expectedName, name)
+				}
+			}
+		})
+	}
+}
+
+func TestAssert_withNaming(t *testing.T) {
+	type customer struct {
+		CustomerID string `json:"customer_id"`
+		Name       string `json:"name"`
+	}
+	type order struct {
+		Customer customer `json:"customer"`
+		Items    []string `json:"items"`
+	}
+	actual := order{Customer: customer{CustomerID: "1", Name: "a"}, Items: []string{"x"}}
+	expected := order{Customer: customer{CustomerID: "2", Name: "b"}, Items: []string{"y"}}
+
+	testTable := []struct {
+		name             string
+		customAssertions map[string]AssertionFunc
+		opts             []Option
+		expectedMatch    bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test with json tag names in rules and messages",
+			customAssertions: map[string]AssertionFunc{
+				"$.customer.customer_id": SkipAssertion,
+				"$.items[]":              SkipAssertion,
+			},
+			opts:            []Option{WithNaming(JSONTagNames), WithStrictRules()},
+			expectedMatch:   false,
+			expectedMessage: "Path: $.customer.name\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test with custom callback",
+			customAssertions: map[string]AssertionFunc{
+				"$.CUSTOMER.NAME":       SkipAssertion,
+				"$.CUSTOMER.CUSTOMERID": SkipAssertion,
+				"$.ITEMS":               SkipAssertion,
+			},
+			opts: []Option{WithNaming(func(field reflect.StructField) string {
+				return strings.ToUpper(field.Name)
+			})},
+			expectedMatch: true,
+		},
+		{
+			name: "Test with Go names not matching json tag names",
+			customAssertions: map[string]AssertionFunc{
+				"$.Customer": SkipAssertion,
+				"$.Items":    SkipAssertion,
+			},
+			opts:          []Option{WithNaming(JSONTagNames), WithStrictRules()},
+			expectedMatch: false,
+			expectedMessage: "Path: $.customer.customer_id\nExpected: \"2\"\nActual:   \"1\"\n(Should equal)!\n" +
+				"Path: $.customer.name\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n" +
+				"Path: $.items[0]\nExpected: \"y\"\nActual:   \"x\"\n(Should equal)!\n" +
+				"Unused custom assertions:\n" +
+				"\"$.Customer\" matched nothing, did you mean \"$.customer\"?\n" +
+				"\"$.Items\" matched nothing, did you mean \"$.items\"?",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			match, message := Assert(actual, expected, tt.customAssertions, tt.opts...)
+			if match != tt.expectedMatch {
+				t.Errorf("Expected match: %v, got: %v", tt.expectedMatch, match)
+			}
+			// remove anything after the word "Diff" in the message till end of line
+			re := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+			// Replace matched lines with an empty string.
+			message = re.ReplaceAllString(message, "")
+
+			if message != tt.expectedMessage {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expectedMessage, message)
+			}
+		})
+	}
+}
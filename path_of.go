@@ -0,0 +1,129 @@
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// pathOfMaxDepth limits how deep PathOf allocates pointers and slices of recursive types
+const pathOfMaxDepth = 16
+
+// PathOf returns the custom assertion path of the field selected by the selector
+// so rule keys are checked by the compiler and follow field renames.
+// the selector receives a value of T, with every pointer and slice allocated, and returns a pointer to the field.
+// slice elements are written as [], fields of maps can't be selected as map values are not addressable.
+// opts are the options used with Assert which change the paths, e.g. WithNaming(JSONTagNames)
+// PathOf panics if the selector doesn't return a pointer to a field of T.
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		PathOf(func(o *Order) any { return &o.Customer.Address.Zip }): SkipAssertion,  // "$.Customer.Address.Zip"
+//		PathOf(func(o *Order) any { return &o.Items[0].Price }):       AssertFloat64ToDecimalPlaces(2), // "$.Items[].Price"
+//	}
+func PathOf[T any](selector func(*T) any, opts ...Option) string {
+	root := reflect.New(reflect.TypeOf((*T)(nil)).Elem())
+	allocate(root.Elem(), 0)
+
+	selected := reflect.ValueOf(callSelector(selector, root.Interface().(*T)))
+	if selected.Kind() != reflect.Ptr || selected.IsNil() {
+		panic(fmt.Sprintf("assertion.PathOf: selector must return a pointer to a field, got %v", selected.Kind()))
+	}
+
+	path, ok := findPath(newConfig(opts...), root.Elem(), selected.Pointer(), selected.Type().Elem(), "$")
+	if !ok {
+		panic(fmt.Sprintf("assertion.PathOf: selector returned a %s which is not a field of %s", selected.Type(), root.Elem().Type()))
+	}
+	return path
+}
+
+// callSelector calls the selector, turning its panics into a message explaining what PathOf supports
+func callSelector[T any](selector func(*T) any, root *T) any {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Sprintf("assertion.PathOf: selector must only access fields, pointers and slice elements: %v", r))
+		}
+	}()
+	return selector(root)
+}
+
+// allocate sets every settable nil pointer and slice reachable from value to a new value,
+// slices get a single element so the selector can index them
+func allocate(value reflect.Value, depth int) {
+	if depth > pathOfMaxDepth {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() && value.CanSet() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		if !value.IsNil() {
+			allocate(value.Elem(), depth+1)
+		}
+	case reflect.Slice:
+		if value.CanSet() {
+			value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+			allocate(value.Index(0), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			allocate(value.Index(i), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			allocate(value.Field(i), depth+1)
+		}
+	}
+}
+
+// findPath searches the allocated value for the field at the address and of the type returned by the selector
+// and returns its path, writing slice and array indexes as []
+func findPath(cfg config, value reflect.Value, addr uintptr, typ reflect.Type, path string) (string, bool) {
+	if value.UnsafeAddr() == addr && value.Type() == typ {
+		return path, true
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return findPath(cfg, value.Elem(), addr, typ, path)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if found, ok := findPath(cfg, value.Index(i), addr, typ, path+"[]"); ok {
+				return found, true
+			}
+		}
+	case reflect.Struct:
+		if cfg.promote {
+			return findPromotedPath(cfg, value, addr, typ, path)
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if found, ok := findPath(cfg, value.Field(i), addr, typ, path+"."+cfg.fieldName(value.Type().Field(i))); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findPromotedPath searches the fields of the struct, including the promoted ones, for the selected field
+// embedded structs are matched as a whole, their fields are found by their promoted path
+func findPromotedPath(cfg config, value reflect.Value, addr uintptr, typ reflect.Type, path string) (string, bool) {
+	for _, field := range embeddedFields(value.Type()) {
+		fieldValue, err := value.FieldByIndexErr(field.Index)
+		if err != nil {
+			continue
+		}
+		fieldPath, _ := fieldPaths(cfg, value.Type(), field, path)
+		if isEmbeddedStruct(field) {
+			if fieldValue.UnsafeAddr() == addr && fieldValue.Type() == typ {
+				return fieldPath, true
+			}
+			continue
+		}
+		if found, ok := findPath(cfg, fieldValue, addr, typ, fieldPath); ok {
+			return found, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,140 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+type pathOfNode struct {
+	Value string
+	Next  *pathOfNode
+}
+
+type pathOfOrder struct {
+	ID       int
+	Customer *checkRulesCustomer
+	Items    []checkRulesItem
+	Matrix   [2][]int
+	Tags     map[string]string
+	List     pathOfNode
+}
+
+func TestPathOf(t *testing.T) {
+	testTable := []struct {
+		name         string
+		path         string
+		expectedPath string
+	}{
+		{
+			name:         "Test with root",
+			path:         PathOf(func(o *pathOfOrder) any { return o }),
+			expectedPath: "$",
+		},
+		{
+			name:         "Test with field",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.ID }),
+			expectedPath: "$.ID",
+		},
+		{
+			name:         "Test with nested fields through pointers",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.Customer.Address.Zip }),
+			expectedPath: "$.Customer.Address.Zip",
+		},
+		{
+			name:         "Test with struct at the same address as its first field",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.Customer.Address }),
+			expectedPath: "$.Customer.Address",
+		},
+		{
+			name:         "Test with slice element field",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.Items[0].Price }),
+			expectedPath: "$.Items[].Price",
+		},
+		{
+			name:         "Test with slice",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.Items }),
+			expectedPath: "$.Items",
+		},
+		{
+			name:         "Test with nested array and slice",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.Matrix[1][0] }),
+			expectedPath: "$.Matrix[][]",
+		},
+		{
+			name:         "Test with recursive type",
+			path:         PathOf(func(o *pathOfOrder) any { return &o.List.Next.Next.Value }),
+			expectedPath: "$.List.Next.Next.Value",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.path != tt.expectedPath {
+				t.Errorf("Expected path: %s, got: %s", tt.expectedPath, tt.path)
+			}
+		})
+	}
+}
+
+func TestPathOf_withNaming(t *testing.T) {
+	type customer struct {
+		CustomerID string `json:"customer_id"`
+	}
+	type order struct {
+		Customers []customer `json:"customers"`
+	}
+	path := PathOf(func(o *order) any { return &o.Customers[0].CustomerID }, WithNaming(JSONTagNames))
+	if path != "$.customers[].customer_id" {
+		t.Errorf("Expected path: $.customers[].customer_id, got: %s", path)
+	}
+}
+
+func TestPathOf_invalidSelectors(t *testing.T) {
+	testTable := []struct {
+		name            string
+		selector        func(o *pathOfOrder) any
+		expectedMessage string
+	}{
+		{
+			name:            "Test with value instead of pointer",
+			selector:        func(o *pathOfOrder) any { return o.ID },
+			expectedMessage: "selector must return a pointer to a field",
+		},
+		{
+			name:            "Test with pointer outside of the value",
+			selector:        func(o *pathOfOrder) any { return new(int) },
+			expectedMessage: "which is not a field of assertion.pathOfOrder",
+		},
+		{
+			name:            "Test with map access",
+			selector:        func(o *pathOfOrder) any { o.Tags["a"] = ""; return &o.Tags },
+			expectedMessage: "selector must only access fields, pointers and slice elements",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatal("Expected PathOf to panic")
+				}
+				if !strings.Contains(r.(string), tt.expectedMessage) {
+					t.Errorf("Expected panic containing: %s, got: %v", tt.expectedMessage, r)
+				}
+			}()
+			PathOf(tt.selector)
+		})
+	}
+}
+
+func TestPathOf_usableAsRule(t *testing.T) {
+	actual := pathOfOrder{Items: []checkRulesItem{{SKU: "a", Price: 1.001}}}
+	expected := pathOfOrder{Items: []checkRulesItem{{SKU: "a", Price: 1.002}}}
+	customAssertions := map[string]AssertionFunc{
+		PathOf(func(o *pathOfOrder) any { return &o.Items[0].Price }): AssertFloat64ToDecimalPlaces(2),
+	}
+	if match, message := Assert(actual, expected, customAssertions, WithStrictRules()); !match {
+		t.Errorf("Expected match, got:\n%s", message)
+	}
+}
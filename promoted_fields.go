@@ -0,0 +1,232 @@
+package assertion
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// walkPromotedFields compares the fields of the structs, including the fields promoted from embedded structs,
+// registering the embedded path of every promoted field as its alias, see WithPromotedFields
+func (w *walker) walkPromotedFields(actual reflect.Value, expected reflect.Value, path string) (bool, string) {
+	match, message := true, ""
+	typ := actual.Type()
+	// walked holds the embedded structs compared as a whole, their fields are skipped
+	walked := [][]int{}
+	fields := embeddedFields(typ)
+	for i, field := range fields {
+		if w.stop() {
+			break
+		}
+		if hasIndexPrefix(field.Index, walked) {
+			continue
+		}
+		fieldPath, embeddedPath := fieldPaths(w.config, typ, field, path)
+		if fieldPath != embeddedPath {
+			w.aliases[fieldPath] = embeddedPath
+		}
+		names := fieldNames(typ, field.Index)
+		actualField, _ := lookupField(actual, names)
+		expectedField, ok := lookupField(expected, names)
+		// check if expected has the same field, and keep comparing the other fields
+		if !ok {
+			match = false
+			message = formatMessage(message, "%s", w.fail(fieldPath, "Path: %s\nField %s not found in expected", fieldPath, field.Name))
+			continue
+		}
+		if isEmbeddedStruct(field) {
+			// an embedded struct is compared as a whole if it has a custom assertion, is nil or its fields weren't expanded,
+			// else through its fields
+			expanded := i+1 < len(fields) && hasIndexPrefix(fields[i+1].Index, [][]int{field.Index})
+			if expanded && !w.hasPathRule(fieldPath) && !isNilPointer(actualField) && !isNilPointer(expectedField) {
+				continue
+			}
+			walked = append(walked, field.Index)
+		}
+		if fieldMatch, fieldMessage := w.walk(actualField, expectedField, fieldPath); !fieldMatch {
+			match = false
+			message = formatMessage(message, "%s", fieldMessage)
+		}
+	}
+	// report the fields of expected missing in actual, the fields of a missing embedded struct are reported with it
+	missing := [][]int{}
+	for _, field := range embeddedFields(expected.Type()) {
+		if hasIndexPrefix(field.Index, missing) {
+			continue
+		}
+		if _, ok := lookupField(actual, fieldNames(expected.Type(), field.Index)); !ok {
+			if w.stop() {
+				break
+			}
+			missing = append(missing, field.Index)
+			fieldPath, _ := fieldPaths(w.config, expected.Type(), field, path)
+			match = false
+			message = formatMessage(message, "%s", w.fail(fieldPath, "Path: %s\nField %s not found in actual", fieldPath, field.Name))
+		}
+	}
+	return match, message
+}
+
+// embeddedFields returns the fields of the struct type followed by the fields of its embedded structs,
+// each embedded struct is immediately followed by its fields, including the ones shadowed by shallower fields.
+// unlike reflect.VisibleFields, shadowed fields are kept so they are still compared by their embedded path
+func embeddedFields(typ reflect.Type) []reflect.StructField {
+	fields := []reflect.StructField{}
+	// embedding holds the embedded types being walked to stop on recursive embedded pointers
+	embedding := map[reflect.Type]bool{typ: true}
+	var collect func(typ reflect.Type, index []int)
+	collect = func(typ reflect.Type, index []int) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			field.Index = append(append([]int{}, index...), i)
+			fields = append(fields, field)
+			if embedded := derefType(field.Type); isEmbeddedStruct(field) && !embedding[embedded] {
+				embedding[embedded] = true
+				collect(embedded, field.Index)
+				delete(embedding, embedded)
+			}
+		}
+	}
+	collect(typ, nil)
+	return fields
+}
+
+// fieldPaths returns the path of the field reported in messages and its embedded path
+// the reported path is the promoted path if Go promotes the field to the struct, else the embedded path
+func fieldPaths(cfg config, typ reflect.Type, field reflect.StructField, path string) (string, string) {
+	embeddedPath := path
+	parent := typ
+	for _, i := range field.Index {
+		parent = derefType(parent)
+		embeddedField := parent.Field(i)
+		embeddedPath += "." + cfg.fieldName(embeddedField)
+		parent = embeddedField.Type
+	}
+	if len(field.Index) > 1 {
+		if promoted, ok := typ.FieldByName(field.Name); ok && equalIndex(promoted.Index, field.Index) {
+			return path + "." + cfg.fieldName(field), embeddedPath
+		}
+	}
+	return embeddedPath, embeddedPath
+}
+
+// aliasesOf returns the embedded paths the path is also addressed by
+// the path may be below a promoted field, e.g. $.Tags[0] is also addressed by $.BaseModel.Tags[0]
+func (w *walker) aliasesOf(path string) []string {
+	aliases := []string{}
+	for len(w.aliases) > 0 {
+		alias, ok := w.aliasOf(path)
+		if !ok {
+			return aliases
+		}
+		aliases = append(aliases, alias)
+		path = alias
+	}
+	return aliases
+}
+
+// aliasOf returns the embedded path of the longest promoted path prefixing the path
+func (w *walker) aliasOf(path string) (string, bool) {
+	for prefix := path; prefix != ""; prefix = parentPath(prefix) {
+		if alias, ok := w.aliases[prefix]; ok {
+			return alias + path[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// hasPathRule checks if a custom assertion is defined for the path or one of its aliases
+func (w *walker) hasPathRule(path string) bool {
+	for _, candidate := range append([]string{path}, w.aliasesOf(path)...) {
+		if _, ok := customAssertionKey(candidate, nil, w.customAssertions); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parentPath returns the path without its last field, index or embedded document, e.g. $.Items for $.Items[0] and $ for $.Items
+// it returns an empty string for the root
+func parentPath(path string) string {
+	end := strings.LastIndexAny(path, ".[#")
+	if end == -1 {
+		return ""
+	}
+	return path[:end]
+}
+
+// promotedField returns the field of the struct type promoted from an embedded struct and named in paths by name
+func promotedField(cfg config, typ reflect.Type, name string) (reflect.StructField, bool) {
+	for _, field := range embeddedFields(typ) {
+		if promotedPath, embeddedPath := fieldPaths(cfg, typ, field, ""); promotedPath != embeddedPath && promotedPath == "."+name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldNames returns the Go names of the fields along the index, from the struct type to the field
+func fieldNames(typ reflect.Type, index []int) []string {
+	names := make([]string, 0, len(index))
+	for _, i := range index {
+		typ = derefType(typ)
+		names = append(names, typ.Field(i).Name)
+		typ = typ.Field(i).Type
+	}
+	return names
+}
+
+// lookupField returns the field reached by following the names from the struct value, dereferencing embedded pointers
+// the returned value is invalid if an embedded pointer is nil, ok is false if a field is missing
+func lookupField(value reflect.Value, names []string) (reflect.Value, bool) {
+	for _, name := range names {
+		for value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+		if !value.IsValid() {
+			return value, true
+		}
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		if _, ok := value.Type().FieldByName(name); !ok {
+			return reflect.Value{}, false
+		}
+		value = value.FieldByName(name)
+	}
+	return value, true
+}
+
+// isEmbeddedStruct checks if the field is an embedded struct or pointer to struct, other than time.Time which is compared as a whole
+func isEmbeddedStruct(field reflect.StructField) bool {
+	typ := derefType(field.Type)
+	return field.Anonymous && typ.Kind() == reflect.Struct && typ != reflect.TypeOf(time.Time{})
+}
+
+// isNilPointer checks if the value is a nil pointer
+func isNilPointer(value reflect.Value) bool {
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// hasIndexPrefix checks if the index starts with any of the prefixes
+func hasIndexPrefix(index []int, prefixes [][]int) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) <= len(index) && equalIndex(prefix, index[:len(prefix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalIndex checks if both field indexes are equal
+func equalIndex(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,243 @@
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type BaseModel struct {
+	ID        int
+	CreatedAt time.Time
+	Tags      []string
+}
+
+type Audit struct {
+	UpdatedBy string
+}
+
+type promotedOrder struct {
+	BaseModel
+	*Audit
+	ID   string
+	Name string
+}
+
+func TestAssert_withPromotedFields(t *testing.T) {
+	testTime, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
+	actual := promotedOrder{
+		BaseModel: BaseModel{ID: 1, CreatedAt: testTime, Tags: []string{"a"}},
+		Audit:     &Audit{UpdatedBy: "a"},
+		ID:        "a",
+		Name:      "a",
+	}
+
+	testTable := []struct {
+		name             string
+		expected         promotedOrder
+		customAssertions map[string]AssertionFunc
+		opts             []Option
+		expectedMatch    bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test with promoted field not matching",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 1, CreatedAt: testTime.Add(time.Second), Tags: []string{"a"}},
+				Audit:     &Audit{UpdatedBy: "b"},
+				ID:        "a",
+				Name:      "a",
+			},
+			opts:          []Option{WithPromotedFields()},
+			expectedMatch: false,
+			expectedMessage: fmt.Sprintf("Path: $.CreatedAt\nExpected: time.Time(%s)\nActual:   time.Time(%s)\n(Should equal)!\n", testTime.Add(time.Second).Format(time.RFC3339Nano), testTime.Format(time.RFC3339Nano)) +
+				"Path: $.UpdatedBy\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test with promoted field not matching without option",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 1, CreatedAt: testTime, Tags: []string{"a"}},
+				Audit:     &Audit{UpdatedBy: "b"},
+				ID:        "a",
+				Name:      "a",
+			},
+			expectedMatch:   false,
+			expectedMessage: "Path: $.Audit.UpdatedBy\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test with shadowed field keeping its embedded path",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 2, CreatedAt: testTime, Tags: []string{"a"}},
+				Audit:     &Audit{UpdatedBy: "a"},
+				ID:        "b",
+				Name:      "a",
+			},
+			opts:          []Option{WithPromotedFields()},
+			expectedMatch: false,
+			expectedMessage: "Path: $.BaseModel.ID\nExpected: 2\nActual:   1\n(Should equal)!\n" +
+				"Path: $.ID\nExpected: \"b\"\nActual:   \"a\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test with rules on promoted and embedded paths",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 2, CreatedAt: testTime.Add(time.Millisecond), Tags: []string{"b"}},
+				Audit:     &Audit{UpdatedBy: "b"},
+				ID:        "a",
+				Name:      "a",
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAt":        AssertTimeToDuration(time.Second),
+				"$.BaseModel.Tags[]": SkipAssertion,
+				"$.BaseModel.ID":     SkipAssertion,
+				"$.Audit.UpdatedBy":  SkipAssertion,
+			},
+			opts:          []Option{WithPromotedFields(), WithStrictRules()},
+			expectedMatch: true,
+		},
+		{
+			name: "Test with rule on embedded struct",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 2, CreatedAt: testTime.Add(time.Millisecond), Tags: []string{"b"}},
+				Audit:     &Audit{UpdatedBy: "a"},
+				ID:        "a",
+				Name:      "a",
+			},
+			customAssertions: map[string]AssertionFunc{
+				"$.BaseModel": SkipAssertion,
+			},
+			opts:          []Option{WithPromotedFields(), WithStrictRules()},
+			expectedMatch: true,
+		},
+		{
+			name: "Test with nil embedded pointer",
+			expected: promotedOrder{
+				BaseModel: BaseModel{ID: 1, CreatedAt: testTime, Tags: []string{"a"}},
+				ID:        "a",
+				Name:      "a",
+			},
+			opts:            []Option{WithPromotedFields()},
+			expectedMatch:   false,
+			expectedMessage: "Path: $.Audit\nExpected: (*assertion.Audit)(nil)\nActual: &assertion.Audit{UpdatedBy: \"a\"}",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			match, message := Assert(actual, tt.expected, tt.customAssertions, tt.opts...)
+			if match != tt.expectedMatch {
+				t.Errorf("Expected match: %v, got: %v", tt.expectedMatch, match)
+			}
+			// remove anything after the word "Diff" in the message till end of line
+			re := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+			// Replace matched lines with an empty string.
+			message = re.ReplaceAllString(message, "")
+
+			if message != tt.expectedMessage {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestAssert_withPromotedFieldsMissing(t *testing.T) {
+	actual := struct {
+		BaseModel
+		Name string
+	}{BaseModel: BaseModel{ID: 1}, Name: "a"}
+	expected := struct {
+		BaseModel
+		Audit
+		Note string
+	}{BaseModel: BaseModel{ID: 2}}
+
+	match, message := Assert(actual, expected, nil, WithPromotedFields())
+	expectedMessage := "Path: $.ID\nExpected: 2\nActual:   1\n(Should equal)!\n" +
+		"Path: $.Name\nField Name not found in expected\n" +
+		"Path: $.Audit\nField Audit not found in actual\n" +
+		"Path: $.Note\nField Note not found in actual"
+	if match || message != expectedMessage {
+		t.Errorf("Expected message:\n%s\ngot:\n%s", expectedMessage, message)
+	}
+}
+
+func TestCheckRules_withPromotedFields(t *testing.T) {
+	customAssertions := map[string]AssertionFunc{
+		"$.CreatedAt":           AssertTimeToDuration(time.Second),
+		"$.BaseModel.CreatedAt": AssertTimeToDuration(time.Second),
+		"$.UpdatedBy":           SkipAssertion,
+		"$.Tags[]":              SkipAssertion,
+		"$.ID":                  AssertStringWithCleanup(nil),
+	}
+	if err := CheckRules[promotedOrder](customAssertions, WithPromotedFields()); err != nil {
+		t.Errorf("Expected no error, got:\n%v", err)
+	}
+	if err := CheckRules[promotedOrder](customAssertions); err == nil {
+		t.Error("Expected promoted paths to be reported without WithPromotedFields")
+	}
+}
+
+func TestPathOf_withPromotedFields(t *testing.T) {
+	testTable := []struct {
+		name         string
+		path         string
+		expectedPath string
+	}{
+		{
+			name:         "Test with promoted field",
+			path:         PathOf(func(o *promotedOrder) any { return &o.CreatedAt }, WithPromotedFields()),
+			expectedPath: "$.CreatedAt",
+		},
+		{
+			name:         "Test with promoted field through embedded pointer",
+			path:         PathOf(func(o *promotedOrder) any { return &o.UpdatedBy }, WithPromotedFields()),
+			expectedPath: "$.UpdatedBy",
+		},
+		{
+			name:         "Test with shadowed field",
+			path:         PathOf(func(o *promotedOrder) any { return &o.BaseModel.ID }, WithPromotedFields()),
+			expectedPath: "$.BaseModel.ID",
+		},
+		{
+			name:         "Test with embedded struct",
+			path:         PathOf(func(o *promotedOrder) any { return &o.BaseModel }, WithPromotedFields()),
+			expectedPath: "$.BaseModel",
+		},
+		{
+			name:         "Test with promoted field without option",
+			path:         PathOf(func(o *promotedOrder) any { return &o.CreatedAt }),
+			expectedPath: "$.BaseModel.CreatedAt",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.path != tt.expectedPath {
+				t.Errorf("Expected path: %s, got: %s", tt.expectedPath, tt.path)
+			}
+		})
+	}
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	type node struct {
+		*node
+		Value int
+	}
+	fields := embeddedFields(reflect.TypeOf(promotedOrder{}))
+	names := []string{}
+	for _, field := range fields {
+		names = append(names, fmt.Sprintf("%s%v", field.Name, field.Index))
+	}
+	expected := []string{"BaseModel[0]", "ID[0 0]", "CreatedAt[0 1]", "Tags[0 2]", "Audit[1]", "UpdatedBy[1 0]", "ID[2]", "Name[3]"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected fields: %v, got: %v", expected, names)
+	}
+
+	// recursive embedded pointers are not expanded, so they are compared as a whole
+	match, message := Assert(node{node: &node{Value: 1}}, node{node: &node{Value: 2}}, nil, WithPromotedFields())
+	if match {
+		t.Errorf("Expected recursive embedded pointer to be compared, got match with message: %s", message)
+	}
+}
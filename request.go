@@ -0,0 +1,224 @@
+package assertion
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// RequestMatcher is an expected outgoing request, zero fields match any request.
+// it asserts a recorded request with Assert, or routes the requests of a fake server to canned responses, see NewStubHandler
+type RequestMatcher struct {
+	// Method is the expected method, e.g. http.MethodPost
+	Method string
+	// Path is the expected path of the URL, e.g. "/v1/orders"
+	Path string
+	// Query are the expected values of the selected query parameters, in any order, e.g. url.Values{"tag": {"b", "a"}}
+	Query url.Values
+	// Headers are the expected values of the selected headers, the same way as Response.Headers
+	Headers http.Header
+	// JSONBody is the expected JSON body, the same way as Response.JSONBody
+	JSONBody any
+	// FormBody is the expected URL-encoded form body, compared as a document keyed by field,
+	// a field with a single value is a string, e.g. $.name, else a list, e.g. $.tag[1]
+	FormBody url.Values
+	// CustomAssertions are the custom assertions defined for the paths or types of the body, e.g. "$.id": SkipAssertion
+	CustomAssertions map[string]AssertionFunc
+}
+
+// StubResponse is the canned response of a Route
+type StubResponse struct {
+	// Status is the status code, http.StatusOK if zero
+	Status int
+	// Headers are the headers of the response
+	Headers http.Header
+	// Body is the body of the response
+	Body string
+}
+
+// Route serves the response to the requests matching the request matcher, see NewStubHandler
+type Route struct {
+	Request  RequestMatcher
+	Response StubResponse
+}
+
+// Match checks the request against the expectation and returns the result and message listing the differences.
+// the body of the request is read and replaced, so it can be read again.
+func (m RequestMatcher) Match(r *http.Request) (bool, string) {
+	body, err := readBody(&r.Body)
+	if err != nil {
+		return false, fmt.Sprintf("Body can't be read: %v", err)
+	}
+	messages := m.compare(r, body)
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "\n")
+	}
+	return true, ""
+}
+
+// Assert checks the request against the expectation, e.g. a request recorded by a fake server.
+// returns true if the request matches, else the test is failed with t.Errorf listing the differences
+// Example usage:
+//
+//	assertion.RequestMatcher{
+//		Method:   http.MethodPost,
+//		Path:     "/v1/charges",
+//		Query:    url.Values{"expand": {"customer", "invoice"}},
+//		Headers:  http.Header{"Idempotency-Key": {key}},
+//		JSONBody: []byte(`{"amount": 1000, "currency": "eur"}`),
+//	}.Assert(t, recorded)
+func (m RequestMatcher) Assert(t testing.TB, r *http.Request) bool {
+	t.Helper()
+	match, message := m.Match(r)
+	if !match {
+		t.Errorf("Request %s %s doesn't match:\n%s", r.Method, r.URL, message)
+	}
+	return match
+}
+
+// compare returns the messages of the differences between the request and the expectation
+func (m RequestMatcher) compare(r *http.Request, body []byte) []string {
+	messages := []string{}
+	if m.Method != "" && r.Method != m.Method {
+		messages = append(messages, fmt.Sprintf("Method\nExpected: %q\nActual:   %q", m.Method, r.Method))
+	}
+	if m.Path != "" && r.URL.Path != m.Path {
+		messages = append(messages, fmt.Sprintf("Path\nExpected: %q\nActual:   %q", m.Path, r.URL.Path))
+	}
+	messages = append(messages, compareQuery(r.URL.Query(), m.Query)...)
+	messages = append(messages, compareHeaders(r.Header, m.Headers)...)
+	if m.JSONBody != nil {
+		if message := compareJSONBody(body, m.JSONBody, m.CustomAssertions); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if m.FormBody != nil {
+		if message := compareFormBody(body, m.FormBody, m.CustomAssertions); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// compareQuery checks the expected values of the query parameters are the actual values, in any order
+func compareQuery(actual url.Values, expected url.Values) []string {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	messages := []string{}
+	for _, name := range names {
+		actualValues, expectedValues := actual[name], expected[name]
+		if slices.Equal(sortedValues(actualValues), sortedValues(expectedValues)) {
+			continue
+		}
+		if len(actualValues) == 0 {
+			messages = append(messages, fmt.Sprintf("Query parameter %s not found\nExpected: %q", name, expectedValues))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("Query parameter %s\nExpected: %q\nActual:   %q", name, expectedValues, actualValues))
+	}
+	return messages
+}
+
+// sortedValues returns a sorted copy of the values
+func sortedValues(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// compareFormBody compares the body as an URL-encoded form with the expected form, see RequestMatcher.FormBody
+func compareFormBody(body []byte, expected url.Values, customAssertions map[string]AssertionFunc) string {
+	actual, err := url.ParseQuery(string(body))
+	if err != nil {
+		return fmt.Sprintf("Body is not a valid form: %v", err)
+	}
+	if match, message := Assert(formDocument(actual), formDocument(expected), customAssertions, documentOptions(nil)...); !match {
+		return "Body\n" + message
+	}
+	return ""
+}
+
+// formDocument returns the form as a document keyed by field, a single value is a string, else a list
+func formDocument(form url.Values) map[string]any {
+	document := map[string]any{}
+	for name, values := range form {
+		if len(values) == 1 {
+			document[name] = values[0]
+			continue
+		}
+		list := make([]any, len(values))
+		for i, value := range values {
+			list[i] = value
+		}
+		document[name] = list
+	}
+	return document
+}
+
+// NewStubHandler returns a handler serving the response of the first route matching each request,
+// e.g. for a httptest.Server faking an upstream service.
+// a request matching no route fails the test with t.Errorf, reporting the differences with the closest route,
+// the one with the fewest differences, and is answered with 501 Not Implemented.
+// Example usage:
+//
+//	server := httptest.NewServer(assertion.NewStubHandler(t,
+//		assertion.Route{
+//			Request:  assertion.RequestMatcher{Method: http.MethodGet, Path: "/v1/customers/1"},
+//			Response: assertion.StubResponse{Body: `{"id": 1, "name": "Ada"}`},
+//		},
+//		assertion.Route{
+//			Request:  assertion.RequestMatcher{Method: http.MethodPost, Path: "/v1/charges", JSONBody: []byte(`{"amount": 1000}`)},
+//			Response: assertion.StubResponse{Status: http.StatusCreated, Body: `{"id": "ch_1"}`},
+//		},
+//	))
+//	defer server.Close()
+func NewStubHandler(t testing.TB, routes ...Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := readBody(&r.Body)
+		if err != nil {
+			t.Errorf("Request %s %s: body can't be read: %v", r.Method, r.URL, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		closest, closestMessages := -1, []string(nil)
+		for i, route := range routes {
+			messages := route.Request.compare(r, body)
+			if len(messages) == 0 {
+				writeStubResponse(w, route.Response)
+				return
+			}
+			if closest < 0 || len(messages) < len(closestMessages) {
+				closest, closestMessages = i, messages
+			}
+		}
+
+		message := fmt.Sprintf("Unexpected request %s %s, no route is defined", r.Method, r.URL)
+		if closest >= 0 {
+			message = fmt.Sprintf("Unexpected request %s %s, the closest route #%d doesn't match:\n%s", r.Method, r.URL, closest+1, strings.Join(closestMessages, "\n"))
+		}
+		t.Errorf("%s", message)
+		http.Error(w, message, http.StatusNotImplemented)
+	})
+}
+
+// writeStubResponse writes the canned response
+func writeStubResponse(w http.ResponseWriter, response StubResponse) {
+	for name, values := range response.Headers {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(response.Body))
+}
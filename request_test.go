@@ -0,0 +1,212 @@
+package assertion
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestMatcher_Match(t *testing.T) {
+	newRequest := func(method string, target string, contentType string, body string) *http.Request {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		r.Header.Set("Idempotency-Key", "k1")
+		return r
+	}
+
+	testTable := []struct {
+		name            string
+		matcher         RequestMatcher
+		request         *http.Request
+		expectedOk      bool
+		expectedMessage string
+	}{
+		{
+			name: "Test matching JSON request",
+			matcher: RequestMatcher{
+				Method:           http.MethodPost,
+				Path:             "/v1/charges",
+				Query:            url.Values{"expand": {"customer", "invoice"}},
+				Headers:          http.Header{"idempotency-key": {"k1"}},
+				JSONBody:         []byte(`{"amount": 1000, "currency": "eur", "id": "x"}`),
+				CustomAssertions: map[string]AssertionFunc{"$.id": SkipAssertion},
+			},
+			request:    newRequest(http.MethodPost, "/v1/charges?expand=invoice&limit=1&expand=customer", "application/json", `{"currency": "eur", "amount": 1000.0, "id": "ch_1"}`),
+			expectedOk: true,
+		},
+		{
+			name: "Test matching form request",
+			matcher: RequestMatcher{
+				Method:   http.MethodPost,
+				FormBody: url.Values{"name": {"Ada"}, "tag": {"a", "b"}},
+			},
+			request:    newRequest(http.MethodPost, "/v1/customers", "application/x-www-form-urlencoded", "tag=a&name=Ada&tag=b"),
+			expectedOk: true,
+		},
+		{
+			name:       "Test zero matcher",
+			request:    newRequest(http.MethodDelete, "/", "", ""),
+			expectedOk: true,
+		},
+		{
+			name: "Test mismatching request",
+			matcher: RequestMatcher{
+				Method:   http.MethodPut,
+				Path:     "/v1/charges/1",
+				Query:    url.Values{"expand": {"customer"}, "limit": {"10"}},
+				Headers:  http.Header{"Authorization": {"Bearer t"}},
+				JSONBody: map[string]any{"amount": 1000},
+			},
+			request:    newRequest(http.MethodPost, "/v1/charges?expand=invoice", "application/json", `{"amount": 900}`),
+			expectedOk: false,
+			expectedMessage: "Method\nExpected: \"PUT\"\nActual:   \"POST\"\n" +
+				"Path\nExpected: \"/v1/charges/1\"\nActual:   \"/v1/charges\"\n" +
+				"Query parameter expand\nExpected: [\"customer\"]\nActual:   [\"invoice\"]\n" +
+				"Query parameter limit not found\nExpected: [\"10\"]\n" +
+				"Header Authorization not found\nExpected: [\"Bearer t\"]\n" +
+				"Body\nPath: $.amount\nExpected: json.Number(\"1000\")\nActual:   json.Number(\"900\")\n(Should equal)!\n",
+		},
+		{
+			name:            "Test mismatching form request",
+			matcher:         RequestMatcher{FormBody: url.Values{"name": {"Ada"}, "tag": {"a", "c"}}},
+			request:         newRequest(http.MethodPost, "/", "application/x-www-form-urlencoded", "name=Ada&tag=a&tag=b"),
+			expectedOk:      false,
+			expectedMessage: "Body\nPath: $.tag[1]\nExpected: \"c\"\nActual:   \"b\"\n(Should equal)!\n",
+		},
+		{
+			name:            "Test invalid JSON body",
+			matcher:         RequestMatcher{JSONBody: []byte(`{}`)},
+			request:         newRequest(http.MethodPost, "/", "application/json", "name=Ada"),
+			expectedOk:      false,
+			expectedMessage: "Body is not a valid JSON document: invalid character 'a' in literal null (expecting 'u')",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := tt.matcher.Match(tt.request)
+			if ok != tt.expectedOk {
+				t.Fatalf("Match returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("Match returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestRequestMatcher_Assert(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/orders", strings.NewReader("body"))
+	tb := &recordingTB{}
+	if !(RequestMatcher{Method: http.MethodGet}).Assert(tb, r) || len(tb.errors) != 0 {
+		t.Errorf("Assert failed: %v", tb.errors)
+	}
+	if (RequestMatcher{Path: "/v1/customers"}).Assert(tb, r) {
+		t.Errorf("Assert should fail on a different path")
+	}
+	expected := "Request GET /v1/orders doesn't match:\nPath\nExpected: \"/v1/customers\"\nActual:   \"/v1/orders\""
+	if len(tb.errors) != 1 || tb.errors[0] != expected {
+		t.Errorf("Assert reported %q, expected %q", tb.errors, expected)
+	}
+	if body, _ := io.ReadAll(r.Body); string(body) != "body" {
+		t.Errorf("the body should be readable again, got %q", body)
+	}
+}
+
+func TestNewStubHandler(t *testing.T) {
+	tb := &recordingTB{}
+	server := httptest.NewServer(NewStubHandler(tb,
+		Route{
+			Request:  RequestMatcher{Method: http.MethodGet, Path: "/v1/customers/1"},
+			Response: StubResponse{Headers: http.Header{"Content-Type": {"application/json"}}, Body: `{"id": 1}`},
+		},
+		Route{
+			Request:  RequestMatcher{Method: http.MethodPost, Path: "/v1/charges", JSONBody: []byte(`{"amount": 1000}`)},
+			Response: StubResponse{Status: http.StatusCreated, Body: `{"id": "ch_1"}`},
+		},
+	))
+	defer server.Close()
+
+	testTable := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedErrors []string
+	}{
+		{
+			name:           "Test first route",
+			method:         http.MethodGet,
+			path:           "/v1/customers/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id": 1}`,
+		},
+		{
+			name:           "Test route matched by body",
+			method:         http.MethodPost,
+			path:           "/v1/charges",
+			body:           `{"amount": 1000}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id": "ch_1"}`,
+		},
+		{
+			name:           "Test closest route reported",
+			method:         http.MethodPost,
+			path:           "/v1/charges",
+			body:           `{"amount": "1000"}`,
+			expectedStatus: http.StatusNotImplemented,
+			expectedBody:   "Unexpected request POST /v1/charges, the closest route #2 doesn't match:\nBody\nPath: $.amount\nExpected: json.Number(\"1000\")\nActual:   \"1000\"\n(Should equal)!\n",
+			expectedErrors: []string{"Unexpected request POST /v1/charges, the closest route #2 doesn't match:\nBody\nPath: $.amount\nExpected: json.Number(\"1000\")\nActual:   \"1000\"\n(Should equal)!"},
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tb.errors = nil
+			r, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.DefaultClient.Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.expectedStatus {
+				t.Errorf("status %d, expected %d", response.StatusCode, tt.expectedStatus)
+			}
+			if actual := diffRegex.ReplaceAllString(string(body), ""); actual != tt.expectedBody {
+				t.Errorf("body %q, expected %q", actual, tt.expectedBody)
+			}
+			for i := range tb.errors {
+				tb.errors[i] = diffRegex.ReplaceAllString(tb.errors[i], "")
+			}
+			if strings.Join(tb.errors, "\n---\n") != strings.Join(tt.expectedErrors, "\n---\n") {
+				t.Errorf("NewStubHandler reported %q, expected %q", tb.errors, tt.expectedErrors)
+			}
+		})
+	}
+}
+
+func TestNewStubHandler_noRoutes(t *testing.T) {
+	tb := &recordingTB{}
+	recorder := httptest.NewRecorder()
+	NewStubHandler(tb).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	expected := "Unexpected request GET /health, no route is defined"
+	if recorder.Code != http.StatusNotImplemented || len(tb.errors) != 1 || tb.errors[0] != expected {
+		t.Errorf("NewStubHandler returned %d and reported %q, expected %q", recorder.Code, tb.errors, expected)
+	}
+}
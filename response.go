@@ -0,0 +1,187 @@
+package assertion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// maxDumpBody is the number of bytes of the body kept in the response dumps of AssertResponse
+const maxDumpBody = 1024
+
+// Response is the expected response of AssertResponse, zero fields aren't checked
+type Response struct {
+	// Status is the expected status code, e.g. http.StatusOK
+	Status int
+	// Headers are the expected values of the selected headers, names are case-insensitive and
+	// the values must be all the values of the header in the same order, values joined by commas in a single header are split,
+	// e.g. {"Vary": {"Accept, Origin"}} matches Vary: Accept and Vary: Origin but {"Vary": {"Accept"}} doesn't
+	Headers http.Header
+	// ContentType is the expected media type, e.g. "application/json",
+	// parameters like charset are only compared when they are expected, e.g. "text/plain; charset=utf-8"
+	ContentType string
+	// JSONBody is the expected JSON body, a string, []byte or json.RawMessage document, or a value serialized with encoding/json,
+	// compared with the custom assertions the same way as AssertAsJSON
+	JSONBody any
+}
+
+// AssertResponse checks the status, headers, content type and JSON body of the response of a handler or a client,
+// a *httptest.ResponseRecorder or a *http.Response, using the custom assertions defined for the paths of the body,
+// e.g. "$.id": SkipAssertion. returns true if the response matches,
+// else the test is failed with t.Errorf listing the differences and a dump of the response, its body trimmed.
+// the body of a *http.Response is read and replaced, so it can be read again.
+// Example usage:
+//
+//	assertion.AssertResponse(t, recorder, assertion.Response{
+//		Status:      http.StatusCreated,
+//		Headers:     http.Header{"Location": {"/orders/1"}},
+//		ContentType: "application/json",
+//		JSONBody:    []byte(`{"id": 1, "status": "created"}`),
+//	}, map[string]assertion.AssertionFunc{
+//		"$.id": assertion.SkipAssertion,
+//	})
+func AssertResponse[R *httptest.ResponseRecorder | *http.Response](t testing.TB, response R, expected Response, customAssertions map[string]AssertionFunc, opts ...Option) bool {
+	t.Helper()
+	var actual *http.Response
+	switch typed := any(response).(type) {
+	case *httptest.ResponseRecorder:
+		if typed != nil {
+			actual = typed.Result()
+		}
+	case *http.Response:
+		actual = typed
+	}
+	if actual == nil {
+		t.Fatalf("assertion.AssertResponse: the response is nil")
+		return false
+	}
+	body, err := readBody(&actual.Body)
+	if err != nil {
+		t.Fatalf("assertion.AssertResponse: can't read the body of the response: %v", err)
+		return false
+	}
+
+	messages := []string{}
+	if expected.Status != 0 && actual.StatusCode != expected.Status {
+		messages = append(messages, fmt.Sprintf("Status\nExpected: %d\nActual:   %d", expected.Status, actual.StatusCode))
+	}
+	messages = append(messages, compareHeaders(actual.Header, expected.Headers)...)
+	if expected.ContentType != "" {
+		if message := compareContentType(actual.Header.Get("Content-Type"), expected.ContentType); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if expected.JSONBody != nil {
+		if message := compareJSONBody(body, expected.JSONBody, customAssertions, opts...); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) > 0 {
+		t.Errorf("Response doesn't match:\n%s\n\nResponse:\n%s", strings.Join(messages, "\n"), dumpResponse(actual, body))
+		return false
+	}
+	return true
+}
+
+// readBody reads the body and replaces it with a reader of the same bytes, a nil body is empty
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// compareHeaders checks the expected values of the headers are the actual values, see Response.Headers
+func compareHeaders(actual http.Header, expected http.Header) []string {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	messages := []string{}
+	for _, name := range names {
+		actualValues, expectedValues := actual.Values(name), expected[name]
+		if slices.Equal(actualValues, expectedValues) || slices.Equal(headerValues(actualValues), headerValues(expectedValues)) {
+			continue
+		}
+		if len(actualValues) == 0 {
+			messages = append(messages, fmt.Sprintf("Header %s not found\nExpected: %q", http.CanonicalHeaderKey(name), expectedValues))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("Header %s\nExpected: %q\nActual:   %q", http.CanonicalHeaderKey(name), expectedValues, actualValues))
+	}
+	return messages
+}
+
+// headerValues splits the values joined by commas, e.g. "gzip, br" is "gzip" and "br"
+func headerValues(values []string) []string {
+	split := []string{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			split = append(split, strings.TrimSpace(part))
+		}
+	}
+	return split
+}
+
+// compareContentType checks the media type and the expected parameters of the content type, case-insensitively
+func compareContentType(actual string, expected string) string {
+	message := fmt.Sprintf("Content-Type\nExpected: %q\nActual:   %q", expected, actual)
+	expectedType, expectedParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return fmt.Sprintf("Expected Content-Type %q is invalid: %v", expected, err)
+	}
+	actualType, actualParams, err := mime.ParseMediaType(actual)
+	if err != nil || actualType != expectedType {
+		return message
+	}
+	for name, value := range expectedParams {
+		if !strings.EqualFold(actualParams[name], value) {
+			return message
+		}
+	}
+	return ""
+}
+
+// compareJSONBody compares the body as a JSON document with the expected body, see Response.JSONBody
+func compareJSONBody(body []byte, expected any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+	actualDocument, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Sprintf("Body is not a valid JSON document: %v", err)
+	}
+	expectedDocument, err := jsonDocument(expected)
+	if err != nil {
+		return fmt.Sprintf("Expected body can't be compared as JSON: %v", err)
+	}
+	if match, message := Assert(actualDocument, expectedDocument, customAssertions, documentOptions(opts)...); !match {
+		return "Body\n" + message
+	}
+	return ""
+}
+
+// dumpResponse returns the status line, headers and body of the response, the body trimmed to maxDumpBody bytes
+func dumpResponse(response *http.Response, body []byte) string {
+	proto, status := response.Proto, response.Status
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	if status == "" {
+		status = fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode))
+	}
+	headers := &strings.Builder{}
+	_ = response.Header.Write(headers)
+	dump := fmt.Sprintf("%s %s\n%s\n", proto, status, strings.ReplaceAll(headers.String(), "\r\n", "\n"))
+	if len(body) > maxDumpBody {
+		return dump + fmt.Sprintf("%s... (%d more bytes)", body[:maxDumpBody], len(body)-maxDumpBody)
+	}
+	return dump + string(body)
+}
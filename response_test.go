@@ -0,0 +1,131 @@
+package assertion
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func orderHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Location", "/orders/7")
+	w.Header().Add("Vary", "Accept")
+	w.Header().Add("Vary", "Origin")
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, `{"id": 7, "status": "created", "total": 10.5}`)
+}
+
+func TestAssertResponse(t *testing.T) {
+	testTable := []struct {
+		name             string
+		expected         Response
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedErrors   []string
+	}{
+		{
+			name: "Test matching response",
+			expected: Response{
+				Status:      http.StatusCreated,
+				Headers:     http.Header{"location": {"/orders/7"}, "VARY": {"Accept, Origin"}},
+				ContentType: "application/json",
+				JSONBody:    map[string]any{"id": 1, "status": "created", "total": 10.5},
+			},
+			customAssertions: map[string]AssertionFunc{"$.id": SkipAssertion},
+			expectedOk:       true,
+		},
+		{
+			name: "Test expected charset",
+			expected: Response{
+				ContentType: "application/json; charset=UTF-8",
+				JSONBody:    []byte(`{"id": 7, "status": "created", "total": 10.50}`),
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test mismatching response",
+			expected: Response{
+				Status:      http.StatusOK,
+				Headers:     http.Header{"Vary": {"Accept"}, "Cache-Control": {"no-store"}},
+				ContentType: "text/plain",
+				JSONBody:    []byte(`{"id": 7, "status": "paid", "total": 10.5}`),
+			},
+			expectedOk: false,
+			expectedErrors: []string{"Response doesn't match:\n" +
+				"Status\nExpected: 200\nActual:   201\n" +
+				"Header Cache-Control not found\nExpected: [\"no-store\"]\n" +
+				"Header Vary\nExpected: [\"Accept\"]\nActual:   [\"Accept\" \"Origin\"]\n" +
+				"Content-Type\nExpected: \"text/plain\"\nActual:   \"application/json; charset=utf-8\"\n" +
+				"Body\nPath: $.status\nExpected: \"paid\"\nActual:   \"created\"\n(Should equal)!\n\n" +
+				"Response:\nHTTP/1.1 201 Created\nContent-Type: application/json; charset=utf-8\nLocation: /orders/7\nVary: Accept\nVary: Origin\n\n" +
+				`{"id": 7, "status": "created", "total": 10.5}`},
+		},
+		{
+			name:           "Test invalid expected body",
+			expected:       Response{JSONBody: []byte(`{`)},
+			expectedOk:     false,
+			expectedErrors: []string{"Response doesn't match:\nExpected body can't be compared as JSON: unexpected EOF\n\nResponse:\nHTTP/1.1 201 Created\nContent-Type: application/json; charset=utf-8\nLocation: /orders/7\nVary: Accept\nVary: Origin\n\n" + `{"id": 7, "status": "created", "total": 10.5}`},
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			orderHandler(recorder, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+			tb := &recordingTB{}
+			ok := AssertResponse(tb, recorder, tt.expected, tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertResponse returned %v: %v", ok, tb.errors)
+			}
+			for i := range tb.errors {
+				tb.errors[i] = diffRegex.ReplaceAllString(tb.errors[i], "")
+			}
+			if strings.Join(tb.errors, "\n---\n") != strings.Join(tt.expectedErrors, "\n---\n") {
+				t.Errorf("AssertResponse reported:\n%s\nexpected:\n%s", strings.Join(tb.errors, "\n---\n"), strings.Join(tt.expectedErrors, "\n---\n"))
+			}
+		})
+	}
+}
+
+func TestAssertResponse_httpResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(orderHandler))
+	defer server.Close()
+	response, err := http.Post(server.URL+"/orders", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if !AssertResponse(t, response, Response{Status: http.StatusCreated, JSONBody: []byte(`{"id": 7, "status": "created", "total": 10.5}`)}, nil) {
+		return
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil || string(body) != `{"id": 7, "status": "created", "total": 10.5}` {
+		t.Errorf("the body should be readable again, got %q, %v", body, err)
+	}
+}
+
+func TestDumpResponse(t *testing.T) {
+	body := strings.Repeat("a", maxDumpBody+10)
+	dump := dumpResponse(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, []byte(body))
+	expected := "HTTP/1.1 200 OK\n\n" + strings.Repeat("a", maxDumpBody) + "... (10 more bytes)"
+	if dump != expected {
+		t.Errorf("dumpResponse returned %q, expected %q", dump, expected)
+	}
+}
+
+func TestAssertResponse_nil(t *testing.T) {
+	tb := &recordingTB{}
+	if AssertResponse(tb, (*httptest.ResponseRecorder)(nil), Response{Status: http.StatusOK}, nil) {
+		t.Error("AssertResponse should fail on a nil recorder")
+	}
+	if len(tb.errors) != 1 || tb.errors[0] != "assertion.AssertResponse: the response is nil" {
+		t.Errorf("AssertResponse reported: %v", tb.errors)
+	}
+}
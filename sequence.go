@@ -0,0 +1,212 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// SequenceMatcher checks the elements of a slice, e.g. events, built by ContainsInOrder, ContainsOnce, NeverContains
+// or FollowedByWithin and used by AssertSequence or as a custom assertion with SequenceAssertion.
+// the elements are compared with the expected steps using the custom assertions defined for their paths in the slice,
+// e.g. "$[].At": SkipAssertion, or types, the same way as Assert.
+type SequenceMatcher struct {
+	check func(elements []any, customAssertions map[string]AssertionFunc, opts ...Option) string
+}
+
+// ContainsInOrder expects the steps in the same order, other elements may come before, between and after them,
+// e.g. Created, then at some point Paid, then Shipped. reports the first step not found after the steps before it
+func ContainsInOrder(steps ...any) SequenceMatcher {
+	return SequenceMatcher{check: func(elements []any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+		return containsInOrder(elements, steps, sequenceNouns{step: "step", element: "element", sequence: "the sequence"}, customAssertions, opts...)
+	}}
+}
+
+// sequenceNouns names the steps, the elements and the sequence in the messages, e.g. "expected entry" for the steps of AssertLogs
+type sequenceNouns struct {
+	step     string
+	element  string
+	sequence string
+}
+
+// containsInOrder checks the elements contain the steps in order, see ContainsInOrder, and reports the first step not found
+func containsInOrder(elements []any, steps []any, nouns sequenceNouns, customAssertions map[string]AssertionFunc, opts ...Option) string {
+	next := 0
+	for i, step := range steps {
+		found := false
+		for j := next; j < len(elements); j++ {
+			if ok, _, _ := compareElement(elements[j], step, j, customAssertions, opts...); ok {
+				next, found = j+1, true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		where := "in " + nouns.sequence
+		if i > 0 {
+			where = fmt.Sprintf("after %s %d matching %s %d", nouns.element, next-1, nouns.step, i-1)
+		}
+		return fmt.Sprintf("%s%s %d not found %s%s", strings.ToUpper(nouns.step[:1]), nouns.step[1:], i, where,
+			closestElement(nouns.element, elements, indexRange(next, len(elements)), step, customAssertions, opts...))
+	}
+	return ""
+}
+
+// ContainsOnce expects exactly one element matching the step
+func ContainsOnce(step any) SequenceMatcher {
+	return SequenceMatcher{check: func(elements []any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+		found := matchingElements(elements, step, customAssertions, opts...)
+		switch {
+		case len(found) == 0:
+			return "Step not found in the sequence" + closestElement("element", elements, indexRange(0, len(elements)), step, customAssertions, opts...)
+		case len(found) > 1:
+			return fmt.Sprintf("Step found %d times, at elements %s, expected once", len(found), formatIndexes(found))
+		}
+		return ""
+	}}
+}
+
+// NeverContains expects no element matching the step
+func NeverContains(step any) SequenceMatcher {
+	return SequenceMatcher{check: func(elements []any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+		if found := matchingElements(elements, step, customAssertions, opts...); len(found) > 0 {
+			return fmt.Sprintf("Step found at elements %s, expected never", formatIndexes(found))
+		}
+		return ""
+	}}
+}
+
+// FollowedByWithin expects every element matching the first step to be followed by an element matching the next step
+// within the n elements after it, e.g. Paid followed by Shipped within 3 events. the first step must be found
+func FollowedByWithin(first any, next any, n int) SequenceMatcher {
+	return SequenceMatcher{check: func(elements []any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+		found := matchingElements(elements, first, customAssertions, opts...)
+		if len(found) == 0 {
+			return "First step not found in the sequence" + closestElement("element", elements, indexRange(0, len(elements)), first, customAssertions, opts...)
+		}
+		messages := []string{}
+		for _, i := range found {
+			window := indexRange(i+1, min(i+1+n, len(elements)))
+			followed := false
+			for _, j := range window {
+				if ok, _, _ := compareElement(elements[j], next, j, customAssertions, opts...); ok {
+					followed = true
+					break
+				}
+			}
+			if !followed {
+				messages = append(messages, fmt.Sprintf("Element %d matching the first step isn't followed by the next step within %d elements%s",
+					i, n, closestElement("element", elements, window, next, customAssertions, opts...)))
+			}
+		}
+		return strings.Join(messages, "\n")
+	}}
+}
+
+// AssertSequence checks the elements of the actual slice or array with the sequence matcher
+// and returns the result and message reporting where the expected sequence broke
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{"$[].At": SkipAssertion}
+//	match, message := AssertSequence(events, ContainsInOrder(Created{ID: 1}, Paid{ID: 1}, Shipped{ID: 1}), customAssertions)
+//	match, message = AssertSequence(events, NeverContains(Refunded{ID: 1}), customAssertions)
+func AssertSequence(actual any, matcher SequenceMatcher, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	elements, ok := sequenceElements(actual)
+	if !ok {
+		return false, fmt.Sprintf("Actual is not a slice or an array: %T", actual)
+	}
+	if message := matcher.check(elements, customAssertions, opts...); message != "" {
+		return false, message
+	}
+	return true, ""
+}
+
+// SequenceAssertion is a custom assertion function checking the actual slice with the sequence matcher, see AssertSequence,
+// the expected value is ignored. the custom assertions are the ones of the elements, their paths start at the slice, e.g. $[].At
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		"$.Events": SequenceAssertion(ContainsInOrder(Created{ID: 1}, Shipped{ID: 1}), nil),
+//	}
+func SequenceAssertion(matcher SequenceMatcher, customAssertions map[string]AssertionFunc) AssertionFunc {
+	return func(actual any, expected ...any) string {
+		_, message := AssertSequence(actual, matcher, customAssertions)
+		return message
+	}
+}
+
+// sequenceElements returns the elements of the slice or array, or of the slice or array it points to
+func sequenceElements(value any) ([]any, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	elements := make([]any, v.Len())
+	for i := range elements {
+		elements[i] = v.Index(i).Interface()
+	}
+	return elements, true
+}
+
+// matchingElements returns the indexes of the elements matching the step
+func matchingElements(elements []any, step any, customAssertions map[string]AssertionFunc, opts ...Option) []int {
+	found := []int{}
+	for i, element := range elements {
+		if ok, _, _ := compareElement(element, step, i, customAssertions, opts...); ok {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// compareElement compares the elements at the index of the slices, e.g. $[2].message,
+// and returns the result, message and number of failures.
+// elements of other types don't match, e.g. the events of a []Event, and count as the most failures
+func compareElement(actual any, expected any, index int, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string, int) {
+	path := fmt.Sprintf("$[%d]", index)
+	if reflect.TypeOf(actual) != reflect.TypeOf(expected) {
+		return false, fmt.Sprintf("Path: %s\nExpected type: %T\nActual type:   %T", path, expected, actual), math.MaxInt
+	}
+	w := newWalker(customAssertions, opts...)
+	match, message := w.walk(reflect.ValueOf(actual), reflect.ValueOf(expected), path)
+	return match, message, w.failures
+}
+
+// closestElement returns the differences with the candidate element with the fewest failures, named by name in the message
+// and introduced by a comma, or an empty string if there are no candidates
+func closestElement(name string, elements []any, candidates []int, expected any, customAssertions map[string]AssertionFunc, opts ...Option) string {
+	closest, closestMessage, closestFailures := -1, "", 0
+	for _, j := range candidates {
+		_, message, failures := compareElement(elements[j], expected, j, customAssertions, opts...)
+		if closest < 0 || failures < closestFailures {
+			closest, closestMessage, closestFailures = j, message, failures
+		}
+	}
+	if closest < 0 {
+		return ""
+	}
+	return fmt.Sprintf(", the closest %s %d doesn't match:\n%s", name, closest, closestMessage)
+}
+
+// indexRange returns the indexes from from to to, to excluded
+func indexRange(from int, to int) []int {
+	indexes := []int{}
+	for i := from; i < to; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// formatIndexes lists the indexes, e.g. 2, 5
+func formatIndexes(indexes []int) string {
+	items := make([]string, len(indexes))
+	for i, index := range indexes {
+		items[i] = fmt.Sprint(index)
+	}
+	return strings.Join(items, ", ")
+}
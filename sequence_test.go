@@ -0,0 +1,178 @@
+package assertion
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+type orderEvent interface{}
+
+type orderCreated struct {
+	OrderID int
+	At      time.Time
+}
+
+type orderPaid struct {
+	OrderID int
+	Amount  float64
+	At      time.Time
+}
+
+type orderShipped struct {
+	OrderID int
+	At      time.Time
+}
+
+type orderViewed struct {
+	OrderID int
+	At      time.Time
+}
+
+func TestAssertSequence(t *testing.T) {
+	at := time.Date(2021, time.January, 1, 10, 0, 0, 0, time.UTC)
+	events := []orderEvent{
+		orderCreated{OrderID: 1, At: at},
+		orderViewed{OrderID: 1, At: at.Add(time.Minute)},
+		orderPaid{OrderID: 1, Amount: 10.5, At: at.Add(2 * time.Minute)},
+		orderViewed{OrderID: 1, At: at.Add(3 * time.Minute)},
+		orderViewed{OrderID: 1, At: at.Add(4 * time.Minute)},
+		orderShipped{OrderID: 1, At: at.Add(5 * time.Minute)},
+	}
+	skipTimes := map[string]AssertionFunc{"$[].At": SkipAssertion}
+
+	testTable := []struct {
+		name            string
+		actual          any
+		matcher         SequenceMatcher
+		expectedOk      bool
+		expectedMessage string
+	}{
+		{
+			name:       "Test contains in order",
+			actual:     events,
+			matcher:    ContainsInOrder(orderCreated{OrderID: 1}, orderPaid{OrderID: 1, Amount: 10.5}, orderShipped{OrderID: 1}),
+			expectedOk: true,
+		},
+		{
+			name:       "Test contains in order from a pointer",
+			actual:     &events,
+			matcher:    ContainsInOrder(orderCreated{OrderID: 1}, orderShipped{OrderID: 1}),
+			expectedOk: true,
+		},
+		{
+			name:       "Test contains in order with steps out of order",
+			actual:     events,
+			matcher:    ContainsInOrder(orderPaid{OrderID: 1, Amount: 10.5}, orderCreated{OrderID: 1}),
+			expectedOk: false,
+			expectedMessage: "Step 1 not found after element 2 matching step 0, the closest element 3 doesn't match:\n" +
+				"Path: $[3]\nExpected type: assertion.orderCreated\nActual type:   assertion.orderViewed",
+		},
+		{
+			name:       "Test contains in order with a different step",
+			actual:     events,
+			matcher:    ContainsInOrder(orderCreated{OrderID: 1}, orderPaid{OrderID: 1, Amount: 12}),
+			expectedOk: false,
+			expectedMessage: "Step 1 not found after element 0 matching step 0, the closest element 2 doesn't match:\n" +
+				"Path: $[2].Amount\nExpected: 12\nActual:   10.5\n(Should equal)!",
+		},
+		{
+			name:       "Test contains once",
+			actual:     events,
+			matcher:    ContainsOnce(orderPaid{OrderID: 1, Amount: 10.5}),
+			expectedOk: true,
+		},
+		{
+			name:            "Test contains more than once",
+			actual:          events,
+			matcher:         ContainsOnce(orderViewed{OrderID: 1}),
+			expectedOk:      false,
+			expectedMessage: "Step found 3 times, at elements 1, 3, 4, expected once",
+		},
+		{
+			name:       "Test contains once without the step",
+			actual:     events,
+			matcher:    ContainsOnce(orderShipped{OrderID: 2}),
+			expectedOk: false,
+			expectedMessage: "Step not found in the sequence, the closest element 5 doesn't match:\n" +
+				"Path: $[5].OrderID\nExpected: 2\nActual:   1\n(Should equal)!",
+		},
+		{
+			name:       "Test never contains",
+			actual:     events,
+			matcher:    NeverContains(orderPaid{OrderID: 2}),
+			expectedOk: true,
+		},
+		{
+			name:            "Test never contains with the step",
+			actual:          events,
+			matcher:         NeverContains(orderViewed{OrderID: 1}),
+			expectedOk:      false,
+			expectedMessage: "Step found at elements 1, 3, 4, expected never",
+		},
+		{
+			name:       "Test followed by within",
+			actual:     events,
+			matcher:    FollowedByWithin(orderPaid{OrderID: 1, Amount: 10.5}, orderShipped{OrderID: 1}, 3),
+			expectedOk: true,
+		},
+		{
+			name:       "Test not followed by within",
+			actual:     events,
+			matcher:    FollowedByWithin(orderPaid{OrderID: 1, Amount: 10.5}, orderShipped{OrderID: 1}, 2),
+			expectedOk: false,
+			expectedMessage: "Element 2 matching the first step isn't followed by the next step within 2 elements, the closest element 3 doesn't match:\n" +
+				"Path: $[3]\nExpected type: assertion.orderShipped\nActual type:   assertion.orderViewed",
+		},
+		{
+			name:            "Test followed by within without the first step",
+			actual:          []orderEvent{orderCreated{OrderID: 1, At: at}},
+			matcher:         FollowedByWithin(orderPaid{OrderID: 1}, orderShipped{OrderID: 1}, 1),
+			expectedOk:      false,
+			expectedMessage: "First step not found in the sequence, the closest element 0 doesn't match:\nPath: $[0]\nExpected type: assertion.orderPaid\nActual type:   assertion.orderCreated",
+		},
+		{
+			name:            "Test not a slice",
+			actual:          orderCreated{OrderID: 1},
+			matcher:         NeverContains(orderCreated{OrderID: 1}),
+			expectedOk:      false,
+			expectedMessage: "Actual is not a slice or an array: assertion.orderCreated",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertSequence(tt.actual, tt.matcher, skipTimes)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertSequence returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertSequence returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestSequenceAssertion(t *testing.T) {
+	type order struct {
+		ID     int
+		Events []orderEvent
+	}
+	actual := order{ID: 1, Events: []orderEvent{orderCreated{OrderID: 1}, orderViewed{OrderID: 1}, orderShipped{OrderID: 1}}}
+
+	ok, message := Assert(actual, order{ID: 1}, map[string]AssertionFunc{
+		"$.Events": SequenceAssertion(ContainsInOrder(orderCreated{OrderID: 1}, orderShipped{OrderID: 1}), nil),
+	})
+	if !ok {
+		t.Errorf("Assert failed: %s", message)
+	}
+
+	ok, message = Assert(actual, order{ID: 1}, map[string]AssertionFunc{
+		"$.Events": SequenceAssertion(ContainsInOrder(orderShipped{OrderID: 1}, orderCreated{OrderID: 1}), nil),
+	})
+	expected := "Path: $.Events\nStep 1 not found after element 2 matching step 0"
+	if ok || message != expected {
+		t.Errorf("Assert returned %v with message:\n%s\nexpected:\n%s", ok, message, expected)
+	}
+}
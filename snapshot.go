@@ -0,0 +1,320 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// snapshotDir is the directory, relative to the package being tested, holding the snapshots of MatchSnapshot
+const snapshotDir = "testdata/snapshots"
+
+// updateSnapshotsEnv is the environment variable rewriting the snapshots when set to a true value, e.g. UPDATE_SNAPSHOTS=1
+const updateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// updateSnapshotsFlag is the flag rewriting the snapshots when the tests define it, e.g. go test ./... -update,
+// it isn't registered by this package so it doesn't clash with the flags of the tests importing it
+const updateSnapshotsFlag = "update"
+
+// MatchSnapshot compares the actual value with the snapshot stored as JSON in testdata/snapshots/<name>.json
+// using the custom assertions defined for the path or type, so volatile values can be tolerated,
+// e.g. "$.CreatedAt": AssertTimeToDuration(time.Hour) or "$.ID": SkipAssertion.
+// the value is compared as the JSON document it is serialized to, paths follow its keys, e.g. $.created_at for a field tagged json:"created_at",
+// and the values are the ones decoded from JSON: strings, json.Number, bool, nil, map[string]any and []any.
+// custom assertions and normalizers keyed by a type match the values of this type in the actual value,
+// e.g. TimeType: NormalizeTimestamps() matches $.created_at holding a time.Time, other type keys match the types of the document.
+// the normalizers of WithNormalizers are applied before the value is written or compared,
+// so values changing on every run or secrets never land in the snapshot.
+// a missing snapshot is created from the actual value and reported with t.Logf,
+// or fails the test with t.Errorf when the CI environment variable is set, as the snapshot was never committed.
+// running the test with UPDATE_SNAPSHOTS=1, or with -update if the tests define a boolean update flag,
+// rewrites the snapshots instead of comparing them.
+// returns true if the snapshot matches, else the test is failed with t.Errorf
+// Example usage:
+//
+//	assertion.MatchSnapshot(t, "order", order, map[string]assertion.AssertionFunc{
+//		"$.CreatedAt": assertion.AssertTimeToDuration(time.Hour),
+//	}, assertion.WithNormalizers(map[string]assertion.Normalizer{
+//		"$.Token": assertion.Placeholder("<token>"),
+//	}))
+func MatchSnapshot(t testing.TB, name string, actual any, customAssertions map[string]AssertionFunc, opts ...Option) bool {
+	t.Helper()
+	return matchSnapshot(t, snapshotDir, name, actual, customAssertions, opts...)
+}
+
+// matchSnapshot compares the actual value with the snapshot named name in the directory, see MatchSnapshot
+func matchSnapshot(t testing.TB, dir string, name string, actual any, customAssertions map[string]AssertionFunc, opts ...Option) bool {
+	t.Helper()
+	if name == "" || !filepath.IsLocal(name) {
+		t.Fatalf("assertion.MatchSnapshot: invalid snapshot name %q, it must be a relative path inside %s", name, dir)
+		return false
+	}
+	path := filepath.Join(dir, filepath.FromSlash(name)+".json")
+
+	document, types, err := snapshotDocument(actual, newConfig(opts...).normalizers)
+	if err != nil {
+		t.Fatalf("assertion.MatchSnapshot: can't serialize the actual value of snapshot %s: %v", path, err)
+		return false
+	}
+	data, err := encodeSnapshot(document)
+	if err != nil {
+		t.Fatalf("assertion.MatchSnapshot: can't serialize the actual value of snapshot %s: %v", path, err)
+		return false
+	}
+
+	if shouldUpdateSnapshots() {
+		if err := writeSnapshot(path, data); err != nil {
+			t.Fatalf("assertion.MatchSnapshot: %v", err)
+			return false
+		}
+		t.Logf("Updated snapshot %s", path)
+		return true
+	}
+
+	stored, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := writeSnapshot(path, data); err != nil {
+			t.Fatalf("assertion.MatchSnapshot: %v", err)
+			return false
+		}
+		// a snapshot missing in CI was never committed, passing would never compare anything
+		if os.Getenv("CI") != "" {
+			t.Errorf("Snapshot %s was missing, it was created from the actual value, review and commit it", path)
+			return false
+		}
+		t.Logf("Created snapshot %s, review and commit it", path)
+		return true
+	}
+	if err != nil {
+		t.Fatalf("assertion.MatchSnapshot: can't read snapshot %s: %v", path, err)
+		return false
+	}
+
+	expected, err := decodeJSON(stored)
+	if err != nil {
+		t.Errorf("Snapshot %s can't be decoded: %v\nRun the test with %s=1 to rewrite it", path, err, updateSnapshotsEnv)
+		return false
+	}
+	match, message := Assert(document, expected, snapshotRules(customAssertions, types), documentOptions(opts)...)
+	if !match {
+		t.Errorf("Snapshot %s doesn't match:\n%s\nRun the test with %s=1 to rewrite it", path, message, updateSnapshotsEnv)
+	}
+	return match
+}
+
+// shouldUpdateSnapshots checks if the snapshots are rewritten, by the UPDATE_SNAPSHOTS environment variable
+// or the -update flag when the tests define it
+func shouldUpdateSnapshots() bool {
+	if f := flag.Lookup(updateSnapshotsFlag); f != nil {
+		if update, _ := strconv.ParseBool(f.Value.String()); update {
+			return true
+		}
+	}
+	update, _ := strconv.ParseBool(os.Getenv(updateSnapshotsEnv))
+	return update
+}
+
+// writeSnapshot writes the snapshot to the path, creating its directory
+func writeSnapshot(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("can't create the directory of snapshot %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("can't write snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+// snapshotDocument serializes the value to JSON and returns the decoded document with the normalizers applied
+// and the Go types of its values by path, see documentTypes
+func snapshotDocument(value any, normalizers map[string]Normalizer) (any, map[string]reflect.Type, error) {
+	document, err := marshalJSONDocument(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	types := map[string]reflect.Type{}
+	documentTypes(reflect.ValueOf(value), document, "$", types)
+	return normalizeTree(document, normalizers, types, "$"), types, nil
+}
+
+// snapshotRules returns the custom assertions with the rules of Go types replaced by the paths of the document holding them,
+// e.g. TimeType by "$.created_at", as the values of the document are the ones decoded from JSON.
+// rules of types not found in the value are kept to match the types of the document, e.g. json.Number,
+// rules defined for a path take precedence over the rules of the types found at this path
+func snapshotRules(customAssertions map[string]AssertionFunc, types map[string]reflect.Type) map[string]AssertionFunc {
+	rules := make(map[string]AssertionFunc, len(customAssertions))
+	for key, customAssertion := range customAssertions {
+		if isPathRule(key) {
+			rules[key] = customAssertion
+		}
+	}
+	for key, customAssertion := range customAssertions {
+		if isPathRule(key) {
+			continue
+		}
+		found := false
+		for path, typ := range types {
+			if typ.String() != key {
+				continue
+			}
+			found = true
+			if _, ok := customAssertions[normalizePath(path)]; !ok {
+				rules[normalizePath(path)] = customAssertion
+			}
+		}
+		if !found {
+			rules[key] = customAssertion
+		}
+	}
+	return rules
+}
+
+// documentTypes records the Go types of the values serialized to the decoded JSON document by path, e.g. time.Time for $.created_at
+// the value and the document are walked together following the field names of encoding/json,
+// values serialized by MarshalJSON or MarshalText are recorded but not walked, as their documents don't follow their types
+func documentTypes(value reflect.Value, document any, path string, types map[string]reflect.Type) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return
+	}
+	types[path] = value.Type()
+	if isJSONMarshaler(value.Type()) {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		fields, ok := document.(map[string]any)
+		if !ok {
+			return
+		}
+		for name, index := range jsonFields(value.Type()) {
+			if item, ok := fields[name]; ok {
+				if field, err := value.FieldByIndexErr(index); err == nil {
+					documentTypes(field, item, path+"."+name, types)
+				}
+			}
+		}
+	case reflect.Map:
+		fields, ok := document.(map[string]any)
+		if !ok {
+			return
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			name, ok := jsonMapKey(iter.Key())
+			if item, found := fields[name]; ok && found {
+				documentTypes(iter.Value(), item, path+"."+name, types)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := document.([]any)
+		if !ok {
+			return
+		}
+		for i := 0; i < value.Len() && i < len(items); i++ {
+			documentTypes(value.Index(i), items[i], fmt.Sprintf("%s[%d]", path, i), types)
+		}
+	}
+}
+
+// isJSONMarshaler checks if encoding/json serializes the values of the type with MarshalJSON or MarshalText, e.g. time.Time
+func isJSONMarshaler(typ reflect.Type) bool {
+	marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	pointer := reflect.PointerTo(typ)
+	return typ.Implements(marshaler) || typ.Implements(textMarshaler) || pointer.Implements(marshaler) || pointer.Implements(textMarshaler)
+}
+
+// jsonMapKey returns the key of the map entry in the JSON document, the same as encoding/json for string and integer keys
+func jsonMapKey(key reflect.Value) (string, bool) {
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), true
+	}
+	return "", false
+}
+
+// jsonFields returns the indexes of the fields of the struct type serialized by encoding/json by their names in the document.
+// fields of embedded structs without a name in their tag are promoted, the shallowest field wins, then the tagged one,
+// other conflicting fields are left out, the same as encoding/json
+func jsonFields(typ reflect.Type) map[string][]int {
+	type candidate struct {
+		index  []int
+		tagged bool
+	}
+	candidates := map[string][]candidate{}
+	var collect func(typ reflect.Type, index []int, visited map[reflect.Type]bool)
+	collect = func(typ reflect.Type, index []int, visited map[reflect.Type]bool) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			fieldIndex := append(append([]int{}, index...), i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if embedded := derefType(field.Type); field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+				if !visited[embedded] {
+					visited[embedded] = true
+					collect(embedded, fieldIndex, visited)
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			candidates[name] = append(candidates[name], candidate{index: fieldIndex, tagged: tag != "" && !strings.HasPrefix(tag, ",")})
+		}
+	}
+	collect(typ, nil, map[reflect.Type]bool{typ: true})
+
+	fields := map[string][]int{}
+	for name, found := range candidates {
+		sort.SliceStable(found, func(i, j int) bool {
+			if len(found[i].index) != len(found[j].index) {
+				return len(found[i].index) < len(found[j].index)
+			}
+			return found[i].tagged && !found[j].tagged
+		})
+		if len(found) > 1 && len(found[0].index) == len(found[1].index) && found[0].tagged == found[1].tagged {
+			continue
+		}
+		fields[name] = found[0].index
+	}
+	return fields
+}
+
+// encodeSnapshot returns the indented JSON of the document, without escaping HTML characters so placeholders stay readable
+func encodeSnapshot(document any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,345 @@
+package assertion
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// update is the update flag the tests of a package importing assertion may define, it must not clash with the package
+var update = flag.Bool("update", false, "rewrite the snapshots")
+
+// recordingTB records the errors and logs of MatchSnapshot instead of failing the test
+type recordingTB struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, a ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, a...))
+}
+
+func (r *recordingTB) Fatalf(format string, a ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, a...))
+}
+
+func (r *recordingTB) Logf(format string, a ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, a...))
+}
+
+type snapshotOrder struct {
+	ID        string
+	CreatedAt time.Time
+	Items     []string
+	Note      string `json:"-"`
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	createdAt := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	stored := snapshotOrder{ID: "1", CreatedAt: createdAt, Items: []string{"a", "b"}, Note: "x"}
+
+	testTable := []struct {
+		name             string
+		actual           any
+		customAssertions map[string]AssertionFunc
+		update           bool
+		expectedOk       bool
+		expectedErrors   []string
+		expectedSnapshot string
+	}{
+		{
+			name:       "Test matching snapshot",
+			actual:     snapshotOrder{ID: "1", CreatedAt: createdAt, Items: []string{"a", "b"}, Note: "y"},
+			expectedOk: true,
+		},
+		{
+			name:       "Test not matching snapshot",
+			actual:     snapshotOrder{ID: "2", CreatedAt: createdAt, Items: []string{"a", "c"}},
+			expectedOk: false,
+			expectedErrors: []string{
+				"Path: $.ID",
+				"Path: $.Items[1]",
+				"Run the test with UPDATE_SNAPSHOTS=1 to rewrite it",
+			},
+		},
+		{
+			name:   "Test volatile values tolerated by custom assertions",
+			actual: snapshotOrder{ID: "2", CreatedAt: createdAt.Add(time.Minute), Items: []string{"a", "b"}},
+			customAssertions: map[string]AssertionFunc{
+				"$.ID":        SkipAssertion,
+				"$.CreatedAt": AssertTimeToDuration(time.Hour),
+			},
+			expectedOk: true,
+		},
+		{
+			name:             "Test updating snapshot",
+			actual:           snapshotOrder{ID: "2", CreatedAt: createdAt, Items: []string{}},
+			update:           true,
+			expectedOk:       true,
+			expectedSnapshot: "{\n  \"CreatedAt\": \"2021-01-01T10:00:00Z\",\n  \"ID\": \"2\",\n  \"Items\": []\n}\n",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CI", "")
+			dir := t.TempDir()
+			recorder := &recordingTB{}
+			if ok := matchSnapshot(recorder, dir, "order", stored, nil); !ok {
+				t.Fatalf("creating the snapshot failed: %v", recorder.errors)
+			}
+			if tt.update {
+				t.Setenv(updateSnapshotsEnv, "1")
+			}
+
+			recorder = &recordingTB{}
+			ok := matchSnapshot(recorder, dir, "order", tt.actual, tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("MatchSnapshot returned %v, errors: %v", ok, recorder.errors)
+			}
+			message := strings.Join(recorder.errors, "\n")
+			if tt.expectedOk && message != "" {
+				t.Errorf("MatchSnapshot reported errors on a match: %s", message)
+			}
+			for _, expected := range tt.expectedErrors {
+				if !strings.Contains(message, expected) {
+					t.Errorf("MatchSnapshot errors don't contain %q:\n%s", expected, message)
+				}
+			}
+			if tt.expectedSnapshot != "" {
+				data, err := os.ReadFile(filepath.Join(dir, "order.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != tt.expectedSnapshot {
+					t.Errorf("MatchSnapshot wrote:\n%s\nexpected:\n%s", data, tt.expectedSnapshot)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldUpdateSnapshots(t *testing.T) {
+	t.Setenv(updateSnapshotsEnv, "")
+	if shouldUpdateSnapshots() {
+		t.Fatalf("snapshots should be compared by default")
+	}
+	if err := flag.Set("update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { *update = false }()
+	if !shouldUpdateSnapshots() {
+		t.Errorf("snapshots should be updated with -update")
+	}
+}
+
+func TestMatchSnapshot_missing(t *testing.T) {
+	t.Setenv("CI", "")
+	dir := t.TempDir()
+	recorder := &recordingTB{}
+	if ok := matchSnapshot(recorder, dir, "nested/order", map[string]int{"b": 2, "a": 1}, nil); !ok {
+		t.Fatalf("MatchSnapshot failed on a missing snapshot: %v", recorder.errors)
+	}
+	path := filepath.Join(dir, "nested", "order.json")
+	if len(recorder.logs) != 1 || !strings.Contains(recorder.logs[0], "Created snapshot "+path) {
+		t.Errorf("MatchSnapshot didn't report the created snapshot: %v", recorder.logs)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\n  \"a\": 1,\n  \"b\": 2\n}\n"; string(data) != expected {
+		t.Errorf("MatchSnapshot wrote:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	// in CI, the snapshot was never committed
+	t.Setenv("CI", "true")
+	recorder = &recordingTB{}
+	if ok := matchSnapshot(recorder, dir, "other", map[string]int{"a": 1}, nil); ok {
+		t.Error("MatchSnapshot should fail on a missing snapshot in CI")
+	}
+	path = filepath.Join(dir, "other.json")
+	if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], "Snapshot "+path+" was missing") {
+		t.Errorf("MatchSnapshot didn't report the missing snapshot: %v", recorder.errors)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("MatchSnapshot didn't create the missing snapshot: %v", err)
+	}
+}
+
+func TestMatchSnapshot_errors(t *testing.T) {
+	testTable := []struct {
+		name          string
+		snapshotName  string
+		stored        string
+		actual        any
+		expectedError string
+	}{
+		{
+			name:          "Test invalid name",
+			snapshotName:  "../order",
+			actual:        1,
+			expectedError: `invalid snapshot name "../order"`,
+		},
+		{
+			name:          "Test value not serializable",
+			snapshotName:  "order",
+			actual:        func() {},
+			expectedError: "can't serialize the actual value",
+		},
+		{
+			name:          "Test snapshot not decodable",
+			snapshotName:  "order",
+			stored:        `{"ID": "1"`,
+			actual:        snapshotOrder{},
+			expectedError: "can't be decoded: unexpected EOF",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.stored != "" {
+				if err := os.WriteFile(filepath.Join(dir, tt.snapshotName+".json"), []byte(tt.stored), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			recorder := &recordingTB{}
+			if ok := matchSnapshot(recorder, dir, tt.snapshotName, tt.actual, nil); ok {
+				t.Fatal("MatchSnapshot should fail")
+			}
+			if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], tt.expectedError) {
+				t.Errorf("MatchSnapshot errors don't contain %q: %v", tt.expectedError, recorder.errors)
+			}
+		})
+	}
+}
+
+func TestMatchSnapshot_normalizers(t *testing.T) {
+	type session struct {
+		ID        string    `json:"id"`
+		Token     string    `json:"token"`
+		Links     []string  `json:"links"`
+		ExpiresAt time.Time `json:"expires_at"`
+		Count     int       `json:"count"`
+	}
+	opts := []Option{WithNormalizers(map[string]Normalizer{
+		"$.id":         NormalizeUUIDs(),
+		"$.token":      Placeholder("<token>"),
+		"$.links[]":    NormalizeUUIDs(),
+		"$.expires_at": NormalizeTimestamps(),
+	})}
+	first := session{
+		ID:        "0b6f3c2e-8a4d-4f1e-9c3b-2d5e7f9a1b3c",
+		Token:     "secret",
+		Links:     []string{"/sessions/0b6f3c2e-8a4d-4f1e-9c3b-2d5e7f9a1b3c"},
+		ExpiresAt: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC),
+		Count:     1,
+	}
+	second := session{
+		ID:        "7d2a9e4b-1c3f-4a5d-8e6b-9f0a1b2c3d4e",
+		Token:     "other secret",
+		Links:     []string{"/sessions/7d2a9e4b-1c3f-4a5d-8e6b-9f0a1b2c3d4e"},
+		ExpiresAt: time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC),
+		Count:     1,
+	}
+
+	t.Setenv("CI", "")
+	dir := t.TempDir()
+	recorder := &recordingTB{}
+	if ok := matchSnapshot(recorder, dir, "session", first, nil, opts...); !ok {
+		t.Fatalf("creating the snapshot failed: %v", recorder.errors)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "session.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "count": 1,
+  "expires_at": "<timestamp>",
+  "id": "<uuid>",
+  "links": [
+    "/sessions/<uuid>"
+  ],
+  "token": "<token>"
+}
+`
+	if string(data) != expected {
+		t.Errorf("MatchSnapshot wrote:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	if ok := matchSnapshot(recorder, dir, "session", second, nil, opts...); !ok {
+		t.Errorf("MatchSnapshot failed on normalized values: %v", recorder.errors)
+	}
+	second.Count = 2
+	if ok := matchSnapshot(recorder, dir, "session", second, nil, opts...); ok {
+		t.Error("MatchSnapshot should fail on values not normalized")
+	}
+}
+
+func TestMatchSnapshot_typeKeys(t *testing.T) {
+	type event struct {
+		Name string    `json:"name"`
+		At   time.Time `json:"at"`
+	}
+	type audit struct {
+		Events  []event        `json:"events"`
+		Created time.Time      `json:"created"`
+		Labels  map[string]int `json:"labels"`
+	}
+	first := audit{
+		Events:  []event{{Name: "created", At: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)}},
+		Created: time.Date(2021, 1, 1, 9, 0, 0, 0, time.UTC),
+		Labels:  map[string]int{"a": 1},
+	}
+	second := first
+	second.Events = []event{{Name: "created", At: first.Events[0].At.Add(time.Hour)}}
+	second.Created = first.Created.Add(time.Hour)
+
+	t.Setenv("CI", "")
+	dir := t.TempDir()
+	recorder := &recordingTB{}
+	normalizers := WithNormalizers(map[string]Normalizer{TimeType: NormalizeTimestamps()})
+	if ok := matchSnapshot(recorder, dir, "audit", first, nil, normalizers); !ok {
+		t.Fatalf("creating the snapshot failed: %v", recorder.errors)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "audit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "created": "<timestamp>",
+  "events": [
+    {
+      "at": "<timestamp>",
+      "name": "created"
+    }
+  ],
+  "labels": {
+    "a": 1
+  }
+}
+`
+	if string(data) != expected {
+		t.Errorf("MatchSnapshot wrote:\n%s\nexpected:\n%s", data, expected)
+	}
+	if ok := matchSnapshot(recorder, dir, "audit", second, nil, normalizers); !ok {
+		t.Errorf("MatchSnapshot failed on normalized times: %v", recorder.errors)
+	}
+
+	recorder = &recordingTB{}
+	if ok := matchSnapshot(recorder, dir, "times", first, nil); !ok {
+		t.Fatalf("creating the snapshot failed: %v", recorder.errors)
+	}
+	customAssertions := map[string]AssertionFunc{TimeType: SkipAssertion, IntType: SkipAssertion}
+	if ok := matchSnapshot(recorder, dir, "times", second, customAssertions, WithStrictRules()); !ok {
+		t.Errorf("MatchSnapshot failed with times skipped by type: %v", recorder.errors)
+	}
+}
@@ -0,0 +1,109 @@
+package assertion
+
+import (
+	"cmp"
+	"reflect"
+	"sort"
+)
+
+// sortedMapKeys returns the keys of the map in a stable order, so maps are walked and reported the same way on every run
+// keys of different kinds, e.g. in map[any]T, are ordered by kind first, see compareValues
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareValues(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+// compareValues returns -1, 0 or 1 as a is ordered before, the same as or after b
+// interfaces are ordered by their dynamic values, nil first, and values of different types by kind then type name
+// numbers are ordered by value, NaN first, strings lexically, false before true, pointers by the values they point to, nil first,
+// channels by capacity then length, structs and arrays by their fields and elements in order.
+// addresses are never compared as they change between runs, pointers only differing by address are ordered the same
+func compareValues(a reflect.Value, b reflect.Value) int {
+	return compareValuesSeen(a, b, map[[2]uintptr]bool{})
+}
+
+// compareValuesSeen compares the values, see compareValues, seen holds the pairs of pointers being compared
+// so cyclic values, e.g. a node pointing to itself, are ordered the same instead of being compared forever
+func compareValuesSeen(a reflect.Value, b reflect.Value, seen map[[2]uintptr]bool) int {
+	if a.Kind() == reflect.Interface || b.Kind() == reflect.Interface {
+		a, b = unwrapInterface(a), unwrapInterface(b)
+	}
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		return compareBool(a.IsValid(), b.IsValid())
+	case a.Kind() != b.Kind():
+		return cmp.Compare(a.Kind(), b.Kind())
+	case a.Type() != b.Type():
+		return cmp.Compare(a.Type().String(), b.Type().String())
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		if c := cmp.Compare(real(a.Complex()), real(b.Complex())); c != 0 {
+			return c
+		}
+		return cmp.Compare(imag(a.Complex()), imag(b.Complex()))
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return compareBool(a.Bool(), b.Bool())
+	case reflect.Ptr:
+		pair := [2]uintptr{a.Pointer(), b.Pointer()}
+		if a.IsNil() || b.IsNil() || pair[0] == pair[1] || seen[pair] {
+			return compareBool(!a.IsNil(), !b.IsNil())
+		}
+		seen[pair] = true
+		return compareValuesSeen(a.Elem(), b.Elem(), seen)
+	case reflect.UnsafePointer:
+		return compareBool(a.Pointer() != 0, b.Pointer() != 0)
+	case reflect.Chan:
+		if c := compareBool(!a.IsNil(), !b.IsNil()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Cap(), b.Cap()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Len(), b.Len())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareValuesSeen(a.Field(i), b.Field(i), seen); c != 0 {
+				return c
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareValuesSeen(a.Index(i), b.Index(i), seen); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// unwrapInterface returns the dynamic value of the interface, or an invalid value if it is nil
+func unwrapInterface(value reflect.Value) reflect.Value {
+	if value.Kind() == reflect.Interface {
+		return value.Elem()
+	}
+	return value
+}
+
+// compareBool orders false before true
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
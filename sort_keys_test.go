@@ -0,0 +1,122 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSortedMapKeys(t *testing.T) {
+	type key struct {
+		A int
+		B string
+	}
+	testTable := []struct {
+		name         string
+		value        any
+		expectedKeys string
+	}{
+		{
+			name:         "Test with string keys",
+			value:        map[string]int{"b": 1, "c": 2, "a": 3},
+			expectedKeys: "[a b c]",
+		},
+		{
+			name:         "Test with int keys",
+			value:        map[int]int{10: 1, -1: 2, 2: 3},
+			expectedKeys: "[-1 2 10]",
+		},
+		{
+			name:         "Test with float keys and NaN",
+			value:        map[float64]int{1.5: 1, math.NaN(): 2, -2: 3},
+			expectedKeys: "[NaN -2 1.5]",
+		},
+		{
+			name:         "Test with bool keys",
+			value:        map[bool]int{true: 1, false: 2},
+			expectedKeys: "[false true]",
+		},
+		{
+			name:         "Test with struct keys",
+			value:        map[key]int{{A: 2, B: "a"}: 1, {A: 1, B: "b"}: 2, {A: 1, B: "a"}: 3},
+			expectedKeys: "[{1 a} {1 b} {2 a}]",
+		},
+		{
+			name:         "Test with array keys",
+			value:        map[[2]int]int{{2, 1}: 1, {1, 2}: 2, {1, 1}: 3},
+			expectedKeys: "[[1 1] [1 2] [2 1]]",
+		},
+		{
+			name:         "Test with mixed kinds",
+			value:        map[any]int{"b": 1, 2: 2, "a": 3, 1: 4, nil: 5, true: 6, 1.5: 7, int64(0): 8},
+			expectedKeys: "[<nil> true 1 2 0 1.5 a b]",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			// sort several times as map iteration order is random
+			for i := 0; i < 10; i++ {
+				keys := []any{}
+				for _, key := range sortedMapKeys(reflect.ValueOf(tt.value)) {
+					keys = append(keys, key.Interface())
+				}
+				if actualKeys := fmt.Sprint(keys); actualKeys != tt.expectedKeys {
+					t.Fatalf("Expected keys: %s, got: %s", tt.expectedKeys, actualKeys)
+				}
+			}
+		})
+	}
+}
+
+func TestSortedMapKeys_pointers(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	// nodes pointing to themselves are ordered by their names before their cycles are reached
+	b, a := &node{Name: "b"}, &node{Name: "a"}
+	b.Next, a.Next = b, a
+	value := map[*node]int{b: 1, nil: 2, {Name: "a", Next: &node{Name: "c"}}: 3, a: 4, {Name: "a"}: 5}
+
+	// sort several times as map iteration order is random
+	for i := 0; i < 10; i++ {
+		names := []string{}
+		for _, key := range sortedMapKeys(reflect.ValueOf(value)) {
+			n := key.Interface().(*node)
+			switch {
+			case n == nil:
+				names = append(names, "<nil>")
+			case n.Next == nil:
+				names = append(names, n.Name)
+			default:
+				names = append(names, n.Name+"->"+n.Next.Name)
+			}
+		}
+		if actualNames := fmt.Sprint(names); actualNames != "[<nil> a a->a a->c b->b]" {
+			t.Fatalf("Expected keys: [<nil> a a->a a->c b->b], got: %s", actualNames)
+		}
+	}
+}
+
+func TestAssert_mapMismatchesOrder(t *testing.T) {
+	actual := map[string]int{"e": 0, "d": 0, "c": 0, "b": 0, "a": 0}
+	expected := map[string]int{"e": 1, "d": 1, "c": 1, "b": 1, "a": 1}
+	expectedMessage := ""
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		expectedMessage = formatMessage(expectedMessage, "Path: $.%s\nExpected: 1\nActual:   0\n(Should equal)!", key)
+	}
+	// remove anything after the word "Diff" in the message till end of line
+	re := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+
+	for i := 0; i < 10; i++ {
+		_, message := Assert(actual, expected, nil)
+		// Replace matched lines with an empty string.
+		message = re.ReplaceAllString(message, "")
+		if message != expectedMessage {
+			t.Fatalf("Expected message:\n%s\ngot:\n%s", expectedMessage, message)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// longStringLength is the length from which mismatching single line strings get a string diff
+	longStringLength = 40
+	// stringDiffContext is the number of runes shown around the first difference of single line strings
+	stringDiffContext = 20
+	// lineDiffContext is the number of unchanged lines shown around the changed lines of multi-line strings
+	lineDiffContext = 2
+	// maxLineDiffCells limits the size of the line diff table, larger strings only report the first differing line
+	maxLineDiffCells = 1 << 20
+)
+
+// stringDiff returns the diff of mismatching strings, or an empty string if they are short enough to be read as is
+// multi-line strings are diffed by lines, other strings show the first differing rune.
+// whitespace, tabs, carriage returns and invisible characters are made visible, see visibleString
+func stringDiff(actual string, expected string) string {
+	if actual == expected {
+		return ""
+	}
+	if strings.Contains(actual, "\n") || strings.Contains(expected, "\n") {
+		return lineDiff(strings.Split(actual, "\n"), strings.Split(expected, "\n"))
+	}
+	if utf8.RuneCountInString(actual) < longStringLength && utf8.RuneCountInString(expected) < longStringLength {
+		return ""
+	}
+	return runeDiff([]rune(actual), []rune(expected))
+}
+
+// runeDiff shows both strings around the first differing rune with a caret under it
+func runeDiff(actual []rune, expected []rune) string {
+	index := 0
+	for index < len(actual) && index < len(expected) && actual[index] == expected[index] {
+		index++
+	}
+	start := index - stringDiffContext
+	if start < 0 {
+		start = 0
+	}
+	prefix := ""
+	if start > 0 {
+		prefix = "..."
+	}
+	caret := len("Expected: ") + utf8.RuneCountInString(prefix+visibleString(string(expected[start:index])))
+	return fmt.Sprintf("String diff at rune %d:\nExpected: %s\nActual:   %s\n%s^",
+		index+1,
+		prefix+runeWindow(expected, start, index+stringDiffContext),
+		prefix+runeWindow(actual, start, index+stringDiffContext),
+		strings.Repeat(" ", caret))
+}
+
+// runeWindow returns the visible runes from start to end, marking the truncated end with ...
+func runeWindow(runes []rune, start int, end int) string {
+	if start > len(runes) {
+		start = len(runes)
+	}
+	if end >= len(runes) {
+		return visibleString(string(runes[start:]))
+	}
+	return visibleString(string(runes[start:end])) + "..."
+}
+
+// lineDiff returns the unified diff of the lines, with - for expected and + for actual lines
+func lineDiff(actual []string, expected []string) string {
+	if len(actual)*len(expected) > maxLineDiffCells {
+		index := 0
+		for index < len(actual) && index < len(expected) && actual[index] == expected[index] {
+			index++
+		}
+		return fmt.Sprintf("String diff at line %d:\n- %s\n+ %s", index+1, lineAt(expected, index), lineAt(actual, index))
+	}
+
+	ops := diffLines(actual, expected)
+	changes := []int{}
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	lines := []string{"String diff (- expected, + actual):"}
+	for k := 0; k < len(changes); {
+		// a hunk holds the changes whose context lines overlap
+		first, last := changes[k], changes[k]
+		for k++; k < len(changes) && changes[k]-lineDiffContext <= last+1+lineDiffContext; k++ {
+			last = changes[k]
+		}
+		hunk := ops[max(0, first-lineDiffContext):min(len(ops), last+1+lineDiffContext)]
+		lines = append(lines, hunkHeader(hunk))
+		for _, op := range hunk {
+			lines = append(lines, string(op.kind)+" "+visibleString(op.line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineOp is a line of the line diff, kind is ' ' for unchanged, '-' for expected and '+' for actual lines
+// expectedLine and actualLine are the 1-based line numbers the line is at
+type lineOp struct {
+	kind         byte
+	line         string
+	expectedLine int
+	actualLine   int
+}
+
+// diffLines returns the shortest edit script from the expected to the actual lines, using the longest common subsequence
+func diffLines(actual []string, expected []string) []lineOp {
+	// common[i][j] is the length of the longest common subsequence of expected[i:] and actual[j:]
+	common := make([][]int, len(expected)+1)
+	for i := range common {
+		common[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	ops := []lineOp{}
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			ops = append(ops, lineOp{' ', expected[i], i + 1, j + 1})
+			i, j = i+1, j+1
+		case j == len(actual) || (i < len(expected) && common[i+1][j] >= common[i][j+1]):
+			ops = append(ops, lineOp{'-', expected[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', actual[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+// hunkHeader returns the @@ -start,count +start,count @@ header of the hunk
+func hunkHeader(ops []lineOp) string {
+	expectedCount, actualCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			expectedCount++
+		}
+		if op.kind != '-' {
+			actualCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", ops[0].expectedLine, expectedCount, ops[0].actualLine, actualCount)
+}
+
+// lineAt returns the visible line at the index, or <missing> past the last line
+func lineAt(lines []string, index int) string {
+	if index >= len(lines) {
+		return "<missing>"
+	}
+	return visibleString(lines[index])
+}
+
+// visibleString makes whitespace and invisible characters visible:
+// spaces as ·, tabs as →, carriage returns as ␍, no-break spaces as ⍽ and other invisible characters as <U+XXXX>
+func visibleString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == ' ':
+			b.WriteRune('·')
+		case r == '\t':
+			b.WriteRune('→')
+		case r == '\r':
+			b.WriteRune('␍')
+		case r == '\u00a0':
+			b.WriteRune('⍽')
+		case r != '\n' && (unicode.Is(unicode.Cf, r) || !unicode.IsPrint(r)):
+			fmt.Fprintf(&b, "<U+%04X>", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
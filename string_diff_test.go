@@ -0,0 +1,87 @@
+package assertion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringDiff(t *testing.T) {
+	testTable := []struct {
+		name         string
+		actual       string
+		expected     string
+		expectedDiff string
+	}{
+		{
+			name:     "Test with short strings",
+			actual:   "test",
+			expected: "test1",
+		},
+		{
+			name:     "Test with equal strings",
+			actual:   strings.Repeat("a", 50),
+			expected: strings.Repeat("a", 50),
+		},
+		{
+			name:     "Test with long strings",
+			actual:   "the quick brown fox jumps over the lazy dog again and again",
+			expected: "the quick brown fox jumps over the lazy cat again and again",
+			expectedDiff: "String diff at rune 41:\n" +
+				"Expected: ...jumps·over·the·lazy·cat·again·and·again\n" +
+				"Actual:   ...jumps·over·the·lazy·dog·again·and·again\n" +
+				"                                 ^",
+		},
+		{
+			name:     "Test with long strings with tab and invisible character",
+			actual:   "column one\tcolumn two​\tcolumn three and more",
+			expected: "column one\tcolumn two\tcolumn three and more",
+			expectedDiff: "String diff at rune 22:\n" +
+				"Expected: ...olumn·one→column·two→column·three·and·mo...\n" +
+				"Actual:   ...olumn·one→column·two<U+200B>→column·three·and·m...\n" +
+				"                                 ^",
+		},
+		{
+			name:     "Test with multi-line strings",
+			actual:   "line 1\nline 2\nline 3\r\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nextra",
+			expected: "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9",
+			expectedDiff: "String diff (- expected, + actual):\n" +
+				"@@ -1,5 +1,5 @@\n" +
+				"  line·1\n" +
+				"  line·2\n" +
+				"- line·3\n" +
+				"+ line·3␍\n" +
+				"  line·4\n" +
+				"  line·5\n" +
+				"@@ -8,2 +8,3 @@\n" +
+				"  line·8\n" +
+				"  line·9\n" +
+				"+ extra",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := stringDiff(tt.actual, tt.expected); diff != tt.expectedDiff {
+				t.Errorf("Expected diff:\n%s\ngot:\n%s", tt.expectedDiff, diff)
+			}
+		})
+	}
+}
+
+func TestVisibleString(t *testing.T) {
+	visible := visibleString("a b\tc\r ​ é")
+	if visible != "a·b→c␍⍽<U+200B><U+2003>é" {
+		t.Errorf("Expected visible string: a·b→c␍⍽<U+200B><U+2003>é, got: %s", visible)
+	}
+}
+
+func TestAssertValue_stringDiff(t *testing.T) {
+	match, message := assertValue("$.Body", nil, reflect.ValueOf("a\nb"), reflect.ValueOf("a\nc"))
+	if match {
+		t.Fatal("Expected strings not to match")
+	}
+	if !strings.HasSuffix(message, "String diff (- expected, + actual):\n@@ -1,2 +1,2 @@\n  a\n- c\n+ b") {
+		t.Errorf("Expected message to end with the string diff, got:\n%s", message)
+	}
+}
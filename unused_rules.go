@@ -0,0 +1,105 @@
+package assertion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agnivade/levenshtein"
+)
+
+// checkUnusedRules applies the unused rules mode to the result of the walk
+// in strict mode the assertion fails if any rule is unused, in warning mode only the message is extended
+// a truncated walk, see WithMaxFailures, didn't visit every path so its unused rules aren't reported
+func (w *walker) checkUnusedRules(match bool, message string) (bool, string) {
+	if w.unusedRules == ignoreUnusedRules || w.truncated {
+		return match, message
+	}
+	unused := w.findUnusedRules()
+	if len(unused) == 0 {
+		return match, message
+	}
+	message = formatMessage(message, "%s", w.reportUnusedRules(unused))
+	if w.unusedRules == failUnusedRules {
+		return false, message
+	}
+	return match, message
+}
+
+// findUnusedRules returns the keys of the custom assertions that matched no path or type while walking
+// keys are sorted so the report doesn't change between runs
+func (w *walker) findUnusedRules() []string {
+	unused := []string{}
+	for key := range w.customAssertions {
+		if !w.usedRules[key] && !w.matchesBelowEmptyPath(key) {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// matchesBelowEmptyPath checks if the rule can match below a nil value or an empty slice or map, see emptyPaths,
+// paths are resolved on the static type of the empty value and types are looked up in the types reachable from it
+func (w *walker) matchesBelowEmptyPath(key string) bool {
+	for path, typ := range w.emptyPaths {
+		if !isPathRule(key) {
+			if types, _ := collectTypes(typ); types[key] != nil {
+				return true
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(key, path)
+		if !ok || rest == "" || !strings.ContainsAny(rest[:1], ".[#") {
+			continue
+		}
+		if _, err := resolveRulePath(w.config, typ, "$"+rest); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// reportUnusedRules formats the unused rules, suggesting the closest visited path or type for each of them
+func (w *walker) reportUnusedRules(unused []string) string {
+	lines := []string{"Unused custom assertions:"}
+	for _, key := range unused {
+		candidates := w.visitedTypes
+		if isPathRule(key) {
+			candidates = w.visitedPaths
+		}
+		if suggestion, ok := suggestRule(key, candidates); ok {
+			lines = append(lines, fmt.Sprintf("%q matched nothing, did you mean %q?", key, suggestion))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%q matched nothing", key))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isPathRule checks if the custom assertion key is a path, otherwise it is a type
+func isPathRule(key string) bool {
+	return strings.HasPrefix(key, "$")
+}
+
+// suggestRule returns the candidate closest to the key by levenshtein distance
+// only candidates within a third of the key length are suggested, ties are broken alphabetically
+func suggestRule(key string, candidates map[string]bool) (string, bool) {
+	sorted := make([]string, 0, len(candidates))
+	for candidate := range candidates {
+		sorted = append(sorted, candidate)
+	}
+	sort.Strings(sorted)
+
+	maxDistance := len(key) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	suggestion, bestDistance := "", maxDistance+1
+	for _, candidate := range sorted {
+		if distance := levenshtein.ComputeDistance(key, candidate); distance < bestDistance {
+			suggestion, bestDistance = candidate, distance
+		}
+	}
+	return suggestion, suggestion != ""
+}
@@ -0,0 +1,198 @@
+package assertion
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAssert_unusedRules(t *testing.T) {
+	type testStruct struct {
+		Name      string
+		CreatedAt time.Time
+	}
+	testTime, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
+	actual := testStruct{Name: "test", CreatedAt: testTime}
+	expected := testStruct{Name: "test", CreatedAt: testTime.Add(time.Millisecond)}
+
+	testTable := []struct {
+		name             string
+		customAssertions map[string]AssertionFunc
+		opts             []Option
+		expectedMatch    bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test with all rules used in strict mode",
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAt": AssertTimeToDuration(time.Second),
+			},
+			opts:          []Option{WithStrictRules()},
+			expectedMatch: true,
+		},
+		{
+			name: "Test with unused rules ignored by default",
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAT": AssertTimeToDuration(time.Second),
+				TimeType:      AssertTimeToDuration(time.Second),
+			},
+			expectedMatch: true,
+		},
+		{
+			name: "Test with typo in path in strict mode",
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAT": AssertTimeToDuration(time.Second),
+				TimeType:      AssertTimeToDuration(time.Second),
+			},
+			opts:            []Option{WithStrictRules()},
+			expectedMatch:   false,
+			expectedMessage: "Unused custom assertions:\n\"$.CreatedAT\" matched nothing, did you mean \"$.CreatedAt\"?",
+		},
+		{
+			name: "Test with unused type and path without suggestion in strict mode",
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAt":        AssertTimeToDuration(time.Second),
+				"$.Something.Else":   SkipAssertion,
+				"time.Tme":           SkipAssertion,
+				"map[string]float64": SkipAssertion,
+			},
+			opts:          []Option{WithStrictRules()},
+			expectedMatch: false,
+			expectedMessage: "Unused custom assertions:\n" +
+				"\"$.Something.Else\" matched nothing\n" +
+				"\"map[string]float64\" matched nothing\n" +
+				"\"time.Tme\" matched nothing, did you mean \"time.Time\"?",
+		},
+		{
+			name: "Test with typo in path in warning mode",
+			customAssertions: map[string]AssertionFunc{
+				"$.CreatedAT": AssertTimeToDuration(time.Second),
+				TimeType:      AssertTimeToDuration(time.Second),
+			},
+			opts:            []Option{WithUnusedRulesWarning()},
+			expectedMatch:   true,
+			expectedMessage: "Unused custom assertions:\n\"$.CreatedAT\" matched nothing, did you mean \"$.CreatedAt\"?",
+		},
+		{
+			name: "Test with typo in path and fail fast stopping at the last value",
+			customAssertions: map[string]AssertionFunc{
+				"$.NAme": SkipAssertion,
+			},
+			opts:          []Option{WithFailFast(), WithStrictRules()},
+			expectedMatch: false,
+			expectedMessage: "Path: $.CreatedAt\nExpected: time.Time(2021-01-01T00:00:00.001Z)\nActual:   time.Time(2021-01-01T00:00:00Z)\n(Should equal)!\n" +
+				"Unused custom assertions:\n\"$.NAme\" matched nothing, did you mean \"$.Name\"?",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			match, message := Assert(actual, expected, tt.customAssertions, tt.opts...)
+			if match != tt.expectedMatch {
+				t.Errorf("Expected match: %v, got: %v", tt.expectedMatch, match)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestAssert_unusedRulesBelowEmptyValues(t *testing.T) {
+	type item struct {
+		SKU   string
+		Price float64
+	}
+	type order struct {
+		DeletedAt *time.Time
+		Items     []item
+		Labels    map[string]item
+	}
+
+	testTable := []struct {
+		name             string
+		customAssertions map[string]AssertionFunc
+		expectedMatch    bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test with rules on nil fields and below empty slices and maps",
+			customAssertions: map[string]AssertionFunc{
+				"$.DeletedAt":       AssertTimeToDuration(time.Second),
+				"$.Items[].Price":   AssertFloat64ToDecimalPlaces(2),
+				"$.Labels.vip.SKU":  SkipAssertion,
+				"float64":           SkipAssertion,
+				"assertion.item":    SkipAssertion,
+				"map[string]string": SkipAssertion,
+			},
+			expectedMatch:   false,
+			expectedMessage: "Unused custom assertions:\n\"map[string]string\" matched nothing",
+		},
+		{
+			name: "Test with typo below an empty slice",
+			customAssertions: map[string]AssertionFunc{
+				"$.Items[].Prise": SkipAssertion,
+			},
+			expectedMatch:   false,
+			expectedMessage: "Unused custom assertions:\n\"$.Items[].Prise\" matched nothing",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			match, message := Assert(order{}, order{Items: []item{}}, tt.customAssertions, WithStrictRules())
+			if match != tt.expectedMatch {
+				t.Errorf("Expected match: %v, got: %v", tt.expectedMatch, match)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestSuggestRule(t *testing.T) {
+	candidates := map[string]bool{
+		"$":             true,
+		"$.Name":        true,
+		"$.CreatedAt":   true,
+		"$.Items[].SKU": true,
+	}
+	testTable := []struct {
+		name               string
+		key                string
+		expectedSuggestion string
+		expectedOk         bool
+	}{
+		{
+			name:               "Test with case typo",
+			key:                "$.createdAt",
+			expectedSuggestion: "$.CreatedAt",
+			expectedOk:         true,
+		},
+		{
+			name:               "Test with index in path",
+			key:                "$.Items[0].SKU",
+			expectedSuggestion: "$.Items[].SKU",
+			expectedOk:         true,
+		},
+		{
+			name:       "Test with no close candidate",
+			key:        "$.Customer.Address.Zip",
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, ok := suggestRule(tt.key, candidates)
+			if ok != tt.expectedOk {
+				t.Errorf("Expected ok: %v, got: %v", tt.expectedOk, ok)
+			}
+			if suggestion != tt.expectedSuggestion {
+				t.Errorf("Expected suggestion: %s, got: %s", tt.expectedSuggestion, suggestion)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AssertXML compares the actual and expected XML documents using the custom assertions defined for the path or type
+// and returns the result and message, the same way as Assert.
+// both documents are parsed into trees where elements are named by their local name, so namespace prefixes don't matter:
+//   - an element without attributes and child elements is its text, e.g. $.Envelope.Body.Order.Total
+//   - attributes are keyed by @ and their local name, e.g. $.Envelope.Body.Order.@id
+//   - the text of an element with attributes or child elements is keyed by #text, e.g. $.Envelope.Body.Order.Note.#text
+//   - repeated child elements are a list, e.g. $.Envelope.Body.Order.Item[1].@sku
+//
+// insignificant whitespace is trimmed, attribute order, comments and namespace declarations are ignored,
+// values are strings, the number and time helpers accept them, e.g. "$.Envelope.Body.Order.Total": AssertFloat64WithTolerance(0.01)
+// Example usage:
+//
+//	customAssertions := map[string]AssertionFunc{
+//		"$.Envelope.Body.Order.@id":       SkipAssertion,
+//		"$.Envelope.Body.Order.CreatedAt": AssertTimeToDuration(time.Minute),
+//	}
+//	match, message := AssertXML(body, expectedBody, customAssertions)
+func AssertXML(actual []byte, expected []byte, customAssertions map[string]AssertionFunc, opts ...Option) (bool, string) {
+	actualDocument, err := decodeXML(actual)
+	if err != nil {
+		return false, fmt.Sprintf("Actual is not a valid XML document: %v", err)
+	}
+	expectedDocument, err := decodeXML(expected)
+	if err != nil {
+		return false, fmt.Sprintf("Expected is not a valid XML document: %v", err)
+	}
+	return Assert(actualDocument, expectedDocument, customAssertions, documentOptions(opts)...)
+}
+
+// xmlElement holds an element while its document is decoded
+type xmlElement struct {
+	name     string
+	attrs    map[string]any
+	children []xmlChild
+	text     strings.Builder
+}
+
+// xmlChild is a child element decoded into its value, see xmlElement.value
+type xmlChild struct {
+	name  string
+	value any
+}
+
+// xmlList groups repeated child elements while decoding, it is distinct from []any so single children are told apart
+type xmlList []any
+
+// decodeXML decodes the XML document into a tree of map[string]any, []any and strings, see AssertXML
+// the tree is a map holding the root element by its name
+func decodeXML(data []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	stack := []*xmlElement{}
+	var root *xmlChild
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			if root != nil {
+				return nil, fmt.Errorf("unexpected element %s after the root element", token.Name.Local)
+			}
+			element := &xmlElement{name: token.Name.Local, attrs: map[string]any{}}
+			for _, attr := range token.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					continue
+				}
+				element.attrs["@"+attr.Name.Local] = attr.Value
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			child := xmlChild{name: element.name, value: element.value()}
+			if len(stack) == 0 {
+				root = &child
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, child)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(token)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+	return map[string]any{root.name: root.value}, nil
+}
+
+// value returns the element as its text if it has no attributes and child elements,
+// else as a map of its attributes, text and child elements, repeated child elements are grouped in a list
+func (e *xmlElement) value() any {
+	text := strings.TrimSpace(e.text.String())
+	if len(e.attrs) == 0 && len(e.children) == 0 {
+		return text
+	}
+	value := e.attrs
+	if text != "" {
+		value["#text"] = text
+	}
+	for _, child := range e.children {
+		switch existing := value[child.name].(type) {
+		case nil:
+			value[child.name] = child.value
+		case xmlList:
+			value[child.name] = append(existing, child.value)
+		default:
+			value[child.name] = xmlList{existing, child.value}
+		}
+	}
+	for name, children := range value {
+		if list, ok := children.(xmlList); ok {
+			value[name] = []any(list)
+		}
+	}
+	return value
+}
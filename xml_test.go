@@ -0,0 +1,103 @@
+package assertion
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAssertXML(t *testing.T) {
+	expected := `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:o="urn:orders">
+	<soap:Body>
+		<o:Order id="1" status="paid">
+			<o:Total>12.50</o:Total>
+			<o:CreatedAt>2021-01-01T10:00:00Z</o:CreatedAt>
+			<o:Item sku="a">first</o:Item>
+			<o:Item sku="b">second</o:Item>
+			<o:Note lang="en">handle with care</o:Note>
+		</o:Order>
+	</soap:Body>
+</soap:Envelope>`
+
+	testTable := []struct {
+		name             string
+		actual           string
+		customAssertions map[string]AssertionFunc
+		expectedOk       bool
+		expectedMessage  string
+	}{
+		{
+			name: "Test other prefixes, whitespace and attribute order",
+			actual: `<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/"><env:Body>
+<Order xmlns="urn:orders" status="paid" id="1"><Total>12.50</Total><CreatedAt>2021-01-01T10:00:00Z</CreatedAt>
+<!-- items --><Item sku="a">first</Item><Item sku="b">second</Item><Note lang="en">  handle with care  </Note></Order>
+</env:Body></env:Envelope>`,
+			expectedOk: true,
+		},
+		{
+			name: "Test mismatches reported with element paths",
+			actual: `<Envelope><Body><Order id="2" status="paid"><Total>12.50</Total><CreatedAt>2021-01-01T10:00:00Z</CreatedAt>
+<Item sku="a">first</Item><Item sku="c">second</Item><Note lang="en">handle with care</Note></Order></Body></Envelope>`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.Envelope.Body.Order.@id\nExpected: \"1\"\nActual:   \"2\"\n(Should equal)!\nPath: $.Envelope.Body.Order.Item[1].@sku\nExpected: \"b\"\nActual:   \"c\"\n(Should equal)!\n",
+		},
+		{
+			name: "Test text of element with attributes",
+			actual: `<Envelope><Body><Order id="1" status="paid"><Total>12.50</Total><CreatedAt>2021-01-01T10:00:00Z</CreatedAt>
+<Item sku="a">first</Item><Item sku="b">second</Item><Note lang="en">fragile</Note></Order></Body></Envelope>`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.Envelope.Body.Order.Note.#text\nExpected: \"handle with care\"\nActual:   \"fragile\"\n(Should equal)!",
+		},
+		{
+			name: "Test rules on element paths",
+			actual: `<Envelope><Body><Order id="2" status="paid"><Total>12.5</Total><CreatedAt>2021-01-01T10:00:30Z</CreatedAt>
+<Item sku="c">first</Item><Item sku="d">second</Item><Note lang="en">handle with care</Note></Order></Body></Envelope>`,
+			customAssertions: map[string]AssertionFunc{
+				"$.Envelope.Body.Order.@id":         SkipAssertion,
+				"$.Envelope.Body.Order.Total":       AssertFloat64WithTolerance(0.001),
+				"$.Envelope.Body.Order.CreatedAt":   AssertTimeToDuration(time.Minute),
+				"$.Envelope.Body.Order.Item[].@sku": SkipAssertion,
+			},
+			expectedOk: true,
+		},
+		{
+			name: "Test missing element",
+			actual: `<Envelope><Body><Order id="1" status="paid"><Total>12.50</Total><CreatedAt>2021-01-01T10:00:00Z</CreatedAt>
+<Item sku="a">first</Item><Item sku="b">second</Item><Comment lang="en">handle with care</Comment></Order></Body></Envelope>`,
+			expectedOk:      false,
+			expectedMessage: "Path: $.Envelope.Body.Order.Comment\nKey Comment not found in expected\nPath: $.Envelope.Body.Order.Note\nKey Note not found in actual",
+		},
+		{
+			name:            "Test invalid document",
+			actual:          `<Envelope><Body></Envelope>`,
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid XML document: XML syntax error on line 1: element <Body> closed by </Envelope>",
+		},
+		{
+			name:            "Test empty document",
+			actual:          `<?xml version="1.0"?>`,
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid XML document: no root element",
+		},
+		{
+			name:            "Test several root elements",
+			actual:          `<Envelope/><Envelope/>`,
+			expectedOk:      false,
+			expectedMessage: "Actual is not a valid XML document: unexpected element Envelope after the root element",
+		},
+	}
+
+	diffRegex := regexp.MustCompile(`(?m)^.*Diff:.*?(\n|$)`)
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message := AssertXML([]byte(tt.actual), []byte(expected), tt.customAssertions)
+			if ok != tt.expectedOk {
+				t.Fatalf("AssertXML returned %v: %s", ok, message)
+			}
+			if message = diffRegex.ReplaceAllString(message, ""); message != tt.expectedMessage {
+				t.Errorf("AssertXML returned message:\n%s\nexpected:\n%s", message, tt.expectedMessage)
+			}
+		})
+	}
+}